@@ -2,9 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -12,12 +16,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/AriT93/ai-agent/config"
 	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/llm"
+	jokemodel "github.com/AriT93/ai-agent/model"
 	"github.com/AriT93/ai-agent/utils"
 
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/llms"
-	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/prompts"
 )
 
@@ -30,10 +36,15 @@ type model struct {
 	spinner     spinner.Model
 	processing  bool
 	jokeClient  *jokeclient.Client
-	llm         llms.Model       // LangChain
-	parser      *chains.LLMChain // Chain for parsing input
-	enhancer    *chains.LLMChain // Chain for enhancing output
+	provider    jokeclient.JokeProvider // Chain of joke sources tried in the configured order
+	llm         llms.Model              // LangChain
+	parser      *llm.QueryParser        // Turns input into a structured query
+	enhancer    *chains.LLMChain        // Chain for enhancing output
 	showingHelp bool
+	cancel      context.CancelFunc // Cancels the outstanding request, nil when idle
+	requestID   int                // Identifies the outstanding request so stale replies are dropped
+	submit      *submitFlow        // The /submit questions being answered, nil otherwise
+	safeMode    bool               // Only show jokes suitable for everyone, added to each request's query
 }
 
 func initialModel() model {
@@ -50,52 +61,50 @@ func initialModel() model {
 	s.Spinner = spinner.Monkey
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
 
-	// Enable debug mode based on environment variable or flag
-	debug := os.Getenv("DEBUG") == "true"
-	jokeClient := jokeclient.NewClient(debug)
+	// Load settings from the config file and environment
+	cfg, initError := config.Load()
+
+	policy, err := loadPolicy(cfg)
+	if initError == nil {
+		initError = err
+	}
+
+	jokeClient := newJokeClient(cfg, policy)
+
+	provider, err := selectProvider(cfg, jokeClient)
+	if initError == nil {
+		initError = err
+	}
 
 	// Initialize LangChain components (with error handling)
-	var llm llms.Model
-	var parser *chains.LLMChain
+	var languageModel llms.Model
+	var parser *llm.QueryParser
 	var enhancer *chains.LLMChain
-	var initError error
-
-	// Check if OPENAI_API_KEY is set
-	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
-		// Initialize OpenAI LLM using the correct function name
-		llm, initError = openai.New(
-			openai.WithToken(apiKey),
-			openai.WithModel("gpt-3.5-turbo"),
-		)
 
-		if initError == nil {
-			// Initialize parser and enhancer chains
-			parserPrompt := prompts.NewPromptTemplate(
-				`Parse this user request for a joke API: {{.input}}
-    
-    Extract these parameters:
-    - category: [programming, misc, dark, pun, spooky, christmas]
-    - type: [single, twopart]
-    - blacklist flags: [nsfw, religious, political, racist, sexist, explicit]
-    
-    For NSFW content, if user specifically requests NSFW jokes, do NOT include nsfw in blacklist.
-    
-    Format your response EXACTLY like this example (one line, no spaces around =):
-    category=programming&type=single&blacklist=religious,political
-    
-    Only include parameters that are specified or implied in the request.`,
-				[]string{"input"},
-			)
-			parser = chains.NewLLMChain(llm, parserPrompt)
-
-			enhancerPrompt := prompts.NewPromptTemplate(
-				"Make this joke more entertaining: {{.output}}",
-				[]string{"output"},
-			)
-			enhancer = chains.NewLLMChain(llm, enhancerPrompt)
+	// Use the configured language model, if any
+	if initError == nil {
+		configured, err := llm.New(cfg.LLM)
+		switch {
+		case errors.Is(err, llm.ErrDisabled):
+		case err != nil:
+			initError = err
+		default:
+			languageModel = configured
 		}
 	}
 
+	if languageModel != nil {
+		// The parser asks for JSON following a schema; the enhancer is a plain chain
+		parser = llm.NewQueryParser(languageModel)
+		parser.Log = jokeClient.WriteDebug
+
+		enhancerPrompt := prompts.NewPromptTemplate(
+			"Make this joke more entertaining: {{.output}}",
+			[]string{"output"},
+		)
+		enhancer = chains.NewLLMChain(languageModel, enhancerPrompt)
+	}
+
 	return model{
 		messages:   []string{"Welcome to AI Assistant!", "Type 'help' for instructions or start typing your request."},
 		viewport:   vp,
@@ -103,26 +112,68 @@ func initialModel() model {
 		spinner:    s,
 		processing: false,
 		jokeClient: jokeClient,
-		llm:        llm,
+		provider:   provider,
+		llm:        languageModel,
 		parser:     parser,
 		enhancer:   enhancer,
+		safeMode:   cfg.SafeMode,
 		err:        initError,
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(textinput.Blink, m.spinner.Tick)
+	return tea.Batch(textinput.Blink, m.spinner.Tick, discoverCmd(m.jokeClient))
+}
+
+// discoverCmd asks JokeAPI which categories, flags and languages it supports,
+// so the parser, help and validation pick up new ones. The built-in lists
+// stay in use if it cannot be reached.
+func discoverCmd(client *jokeclient.Client) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		vocabulary, err := client.Discover(ctx)
+		if client.Debug {
+			if err != nil {
+				client.WriteDebug("DISCOVERY ERROR: %v\n", err)
+			}
+			client.WriteDebug("CATEGORIES AVAILABLE: %s\n", strings.Join(vocabulary.Categories, ","))
+		}
+		return nil
+	}
 }
 
 type jokeResponseMsg struct {
-	joke string
+	id    int              // Request the response belongs to
+	jokes []jokemodel.Joke // The jokes with their metadata, rendered as a numbered list when there are several
+	texts []string         // Text shown for each joke, which LangChain may have enhanced
+	note  string           // Explains any filters dropped to find a match, or a lowered amount
+}
+
+// newJokeResponse builds a reply showing each joke's own text
+func newJokeResponse(id int, jokes ...jokemodel.Joke) jokeResponseMsg {
+	texts := make([]string, len(jokes))
+	for i, joke := range jokes {
+		texts[i] = joke.Text()
+	}
+	return jokeResponseMsg{id: id, jokes: jokes, texts: texts}
+}
+
+// textResponseMsg is a reply shown as-is, such as the result of a command
+type textResponseMsg struct {
+	id   int // Request the response belongs to
+	text string
 }
 
 type errorResponseMsg struct {
+	id  int // Request the error belongs to
 	err error
 }
 
-const helpMessage = `
+// helpTemplate is the help screen, filled in by helpMessage with the
+// categories, flags and languages JokeAPI supports
+const helpTemplate = `
 AI Assistant Help:
 -----------------
 This is a natural language interface for fetching jokes, enhanced with LangChain.
@@ -130,17 +181,29 @@ This is a natural language interface for fetching jokes, enhanced with LangChain
 Commands:
 - Type a request like "Tell me a joke about programming"
 - Add parameters like "category:programming" or "type:twopart" 
+- Type "/joke 42" to fetch a joke by its ID, or "/range 10-50" for one in an ID range
+- Type "/safe on" or "/safe off" to only show jokes suitable for everyone (saved for next time)
+- Type "/status" to check JokeAPI's health, joke counts and remaining rate limit
+- Type "/submit" to submit a joke of your own to JokeAPI, or "/submit --dry-run" to check one without saving it
+- Press Ctrl+X or ESC to cancel a request that is taking too long
 - Type "quit" or press ESC to exit
 - Type "help" to show this message
 
 Parameters:
-- category: [programming, misc, dark, pun, spooky, christmas]
+- category: [%s] (combine several, e.g. "programming or pun")
 - type: [single, twopart]
-- blacklist: [nsfw, religious, political, racist, sexist, explicit]
+- blacklist: [%s]
+- amount: up to 10 jokes at once, e.g. "give me 5 pun jokes"
+- lang: [%s], e.g. "a joke in German"
+- contains: search for a topic, e.g. "a joke about coffee" or "contains:coffee"
 
 Examples:
 "Tell me a programming joke"
 "Give me a twopart joke about christmas"
+"Tell me a programming or pun joke"
+"Give me 5 pun jokes"
+"Tell me a programming joke in German"
+"Tell me a joke about coffee"
 "Tell me a joke but nothing nsfw or political"
 "I'd like something funny about computers, but keep it clean"
 
@@ -150,72 +213,79 @@ LangChain features:
 - Better parameter extraction from complex requests
 `
 
-// Command to fetch a joke asynchronously
-func fetchJokeCmd(client *jokeclient.Client, input string, model model) tea.Cmd {
+// helpMessage returns the help screen listing the current vocabulary
+func helpMessage() string {
+	return fmt.Sprintf(helpTemplate,
+		strings.ToLower(strings.Join(jokeclient.KnownCategories(), ", ")),
+		strings.Join(jokeclient.KnownFlags(), ", "),
+		strings.Join(jokeclient.SupportedLanguages(), ", "),
+	)
+}
+
+// Command to fetch a joke asynchronously. Cancelling ctx abandons the
+// LangChain calls and the API request.
+func fetchJokeCmd(ctx context.Context, id int, client *jokeclient.Client, input string, model model) tea.Cmd {
 	return func() tea.Msg {
-		var parsedInput string
-		var err error
+		var query jokeclient.JokeQuery
 
 		// Use LangChain to parse input if available
-		if model.parser != nil && model.llm != nil {
+		if model.parser != nil {
 			// Log original input to debug
 			if client.Debug {
 				client.WriteDebug("========== LangChain Processing ==========\n")
 				client.WriteDebug("ORIGINAL INPUT: %s\n", input)
 			}
 
-			// Call LangChain parser
-			ctx := context.Background()
-			result, parseErr := chains.Call(ctx, model.parser, map[string]any{
-				"input": input,
-			})
-
+			parsed, parseErr := model.parser.Parse(ctx, input)
 			if parseErr != nil {
-				// Log parsing error but continue with original input
+				// Log parsing error but continue with the built-in parser
 				if client.Debug {
 					client.WriteDebug("LANGCHAIN ERROR: %v\n", parseErr)
 				}
-				parsedInput = input
+				query = jokeclient.ParseQuery(input)
 			} else {
-				// Extract parsed text from LangChain result
-				if text, ok := result["text"].(string); ok {
-					parsedInput = strings.TrimSpace(text)
-
-					// Log the parsed result
-					if client.Debug {
-						client.WriteDebug("LANGCHAIN PARSED: %s\n", parsedInput)
-						client.WriteDebug("PARAMETERS EXTRACTED:\n")
-						params := strings.Split(parsedInput, "&")
-						for _, param := range params {
-							client.WriteDebug("  %s\n", param)
-						}
-					}
-				} else {
-					parsedInput = input
-					if client.Debug {
-						client.WriteDebug("LANGCHAIN OUTPUT FORMAT ERROR: Unable to extract text\n")
-					}
+				query = parsed
+				if client.Debug {
+					client.WriteDebug("LANGCHAIN PARSED: %+v\n", query)
 				}
 			}
 		} else {
-			// Use original input if LangChain is not available
-			parsedInput = input
+			// Use the built-in parser if LangChain is not available
+			query = jokeclient.ParseQuery(input)
+		}
+
+		if ctx.Err() != nil {
+			return errorResponseMsg{id: id, err: ctx.Err()}
+		}
+
+		query.SafeMode = query.SafeMode || model.safeMode
+		if client.Debug {
+			client.WriteDebug("CATEGORIES: %s\n", strings.Join(query.Categories, ","))
+			if query.Contains != "" {
+				client.WriteDebug("SEARCH: %s\n", query.Contains)
+			}
 		}
 
-		// Fetch joke with parsed input
-		joke, err := client.FetchJoke(parsedInput)
+		// Batches are listed as-is without enhancement
+		if query.Amount > 1 {
+			return fetchJokeBatch(ctx, id, client, query, jokeclient.RequestedAmount(input))
+		}
+
+		response, used, err := jokeclient.SearchWithFallback(ctx, model.provider, query)
 		if err != nil {
-			return errorResponseMsg{err: err}
+			return errorResponseMsg{id: id, err: err}
 		}
+		reply := newJokeResponse(id, jokemodel.JokeFromResponse(*response))
+		reply.note = broadenedNote(query, used)
 
 		// Use LangChain to enhance joke if available
 		if model.enhancer != nil && model.llm != nil {
+			joke := reply.texts[0]
 			if client.Debug {
 				client.WriteDebug("ORIGINAL JOKE: %s\n", joke)
 			}
 
 			// Call LangChain enhancer
-			ctx := context.Background()
 			result, enhanceErr := chains.Call(ctx, model.enhancer, map[string]any{
 				"output": joke,
 			})
@@ -231,13 +301,146 @@ func fetchJokeCmd(client *jokeclient.Client, input string, model model) tea.Cmd
 					}
 
 					// Use enhanced joke
-					joke = enhancedJoke
+					reply.texts[0] = enhancedJoke
 				}
 			}
 		}
 
-		return jokeResponseMsg{joke: joke}
+		return reply
+	}
+}
+
+// fetchJokeBatch fetches several jokes in one request. requested is the
+// amount the user asked for, which may be more than the query allows.
+func fetchJokeBatch(ctx context.Context, id int, client *jokeclient.Client, query jokeclient.JokeQuery, requested int) tea.Msg {
+	responses, err := client.FetchJokes(ctx, query)
+	if err != nil {
+		return errorResponseMsg{id: id, err: err}
+	}
+
+	jokes := make([]jokemodel.Joke, len(responses))
+	for i := range responses {
+		jokes[i] = jokemodel.JokeFromResponse(responses[i])
+	}
+
+	reply := newJokeResponse(id, jokes...)
+	if requested > jokeclient.MaxAmount {
+		reply.note = fmt.Sprintf("You asked for %d jokes, but JokeAPI serves at most %d at once.", requested, jokeclient.MaxAmount)
+	}
+	return reply
+}
+
+// renderJokes formats a reply for the chat: a header naming the categories,
+// language and provider, then each joke labelled with its ID and flags. Only
+// jokes from jokeAPI are labelled with IDs, since only those can be fetched
+// again with /joke.
+func renderJokes(msg jokeResponseMsg, jokeAPI string) string {
+	if len(msg.jokes) == 0 {
+		return "AI: No jokes found."
+	}
+
+	categories := []string{}
+	for _, joke := range msg.jokes {
+		if !slices.Contains(categories, string(joke.Category)) {
+			categories = append(categories, string(joke.Category))
+		}
+	}
+
+	first := msg.jokes[0]
+	language := first.Lang
+	if language == "" {
+		language = "en"
+	}
+
+	jokes := make([]string, len(msg.jokes))
+	for i, joke := range msg.jokes {
+		text := msg.texts[i]
+		if joke.Provider == jokeAPI {
+			text = fmt.Sprintf("#%d %s", joke.ID, text)
+		}
+		if !joke.Flags.Empty() {
+			text += fmt.Sprintf(" (flagged: %s)", strings.Join(joke.Flags.Names(), ", "))
+		}
+		jokes[i] = text
+	}
+
+	// Wrap the joke at 72 characters for better display
+	var wrappedJoke string
+	if len(jokes) == 1 {
+		wrappedJoke = utils.WordWrap(jokes[0], 72)
+	} else {
+		items := make([]string, len(jokes))
+		for i, joke := range jokes {
+			items[i] = utils.WordWrap(fmt.Sprintf("%d. %s", i+1, joke), 72)
+		}
+		wrappedJoke = "\n" + strings.Join(items, "\n")
+	}
+	if msg.note != "" {
+		wrappedJoke = utils.WordWrap(msg.note, 72) + "\n" + wrappedJoke
+	}
+
+	return fmt.Sprintf("AI [%s in %s via %s]: %s", strings.Join(categories, ", "), jokeclient.LanguageName(language), first.Provider, wrappedJoke)
+}
+
+// broadenedNote explains which filters were dropped when a search had to be
+// broadened to find a joke, or returns "" if the query was used as given
+func broadenedNote(requested, used jokeclient.JokeQuery) string {
+	if requested.Contains != "" && used.Contains == "" {
+		return fmt.Sprintf("No jokes mentioned %q, so here is one without the search.", requested.Contains)
+	}
+
+	var dropped []string
+	if len(requested.Categories) > 0 && len(used.Categories) == 0 {
+		dropped = append(dropped, strings.Join(requested.Categories, "/")+" category")
+	}
+	if requested.Type != "" && used.Type == "" {
+		dropped = append(dropped, requested.Type+" type")
+	}
+	if len(dropped) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("No jokes about %q matched the %s, so I searched more widely.", requested.Contains, strings.Join(dropped, " and "))
+}
+
+// friendlyError turns known API errors into guidance for the user
+func friendlyError(err error) string {
+	switch {
+	case errors.Is(err, jokemodel.ErrRateLimited):
+		return "JokeAPI is rate limiting requests right now. Wait a minute and try again."
+	case errors.Is(err, jokemodel.ErrInvalidCategory):
+		return "That category isn't available. Type 'help' to see the categories you can ask for."
+	case errors.Is(err, jokeclient.ErrPolicyViolation):
+		return "The jokes found were blocked by the content filters. Try a different category, or turn safe mode off with /safe off."
+	case errors.Is(err, jokemodel.ErrNoMatch):
+		return "No joke matched that request. Try fewer filters, a different category or another search word."
+	}
+	return err.Error()
+}
+
+// startRequest runs the command built by newCmd with a fresh cancellable
+// context and request ID, showing the spinner until it replies
+func (m model) startRequest(newCmd func(ctx context.Context, id int) tea.Cmd) (model, tea.Cmd) {
+	var ctx context.Context
+	ctx, m.cancel = context.WithCancel(context.Background())
+	m.requestID++
+	m.processing = true
+	return m, tea.Batch(newCmd(ctx, m.requestID), m.spinner.Tick)
+}
+
+// cancelRequest abandons the outstanding request, if any, and notes it in the chat
+func (m model) cancelRequest() model {
+	if m.cancel == nil {
+		return m
 	}
+
+	m.cancel()
+	m.cancel = nil
+	m.processing = false
+
+	m.messages = append(m.messages, "Request cancelled.")
+	m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
+	m.viewport.GotoBottom()
+	return m
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -255,9 +458,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+
+		case tea.KeyEsc:
+			// ESC cancels an in-flight request before it quits
+			if m.processing {
+				return m.cancelRequest(), nil
+			}
 			return m, tea.Quit
 
+		case tea.KeyCtrlX:
+			return m.cancelRequest(), nil
+
 		case tea.KeyEnter:
 			input := m.textInput.Value()
 			if input == "" {
@@ -279,14 +492,28 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Reset input
 			m.textInput.Reset()
 
+			// A new prompt replaces any request still in flight
+			m = m.cancelRequest()
+
 			// Add user message to history
 			m.messages = append(m.messages, "You: "+input)
 			m.viewport.SetContent(strings.Join(m.messages, "\n"))
 			m.viewport.GotoBottom()
 
+			// Answers to /submit questions go to the submission flow
+			if m.submit != nil {
+				return m.continueSubmit(input)
+			}
+
+			// Slash commands bypass the natural language parser
+			if strings.HasPrefix(input, "/") {
+				return m.runCommand(input)
+			}
+
 			// Initiate joke fetching
-			m.processing = true
-			return m, tea.Batch(fetchJokeCmd(m.jokeClient, input, m), m.spinner.Tick)
+			return m.startRequest(func(ctx context.Context, id int) tea.Cmd {
+				return fetchJokeCmd(ctx, id, m.jokeClient, input, m)
+			})
 		}
 
 	case spinner.TickMsg:
@@ -295,22 +522,46 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case jokeResponseMsg:
+		// Ignore replies to requests that were cancelled or replaced
+		if msg.id != m.requestID || m.cancel == nil {
+			return m, nil
+		}
+		m.cancel()
+		m.cancel = nil
 		m.processing = false
 
-		// Wrap the joke at 72 characters for better display
-		wrappedJoke := utils.WordWrap(msg.joke, 72)
-		m.messages = append(m.messages, "AI: "+wrappedJoke)
+		m.messages = append(m.messages, renderJokes(msg, m.jokeClient.Name()))
 
 		// Add extra newline for better separation between messages
 		m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
 		m.viewport.GotoBottom()
 		return m, nil
 
+	case textResponseMsg:
+		if msg.id != m.requestID || m.cancel == nil {
+			return m, nil
+		}
+		m.cancel()
+		m.cancel = nil
+		m.processing = false
+
+		// Wrap each line separately so multi-line reports keep their layout
+		lines := strings.Split(msg.text, "\n")
+		for i, line := range lines {
+			lines[i] = utils.WordWrap(line, 72)
+		}
+		return m.addMessage(strings.Join(lines, "\n")), nil
+
 	case errorResponseMsg:
+		if msg.id != m.requestID || m.cancel == nil {
+			return m, nil
+		}
+		m.cancel()
+		m.cancel = nil
 		m.processing = false
 
 		// Wrap error message
-		wrappedError := utils.WordWrap(msg.err.Error(), 72)
+		wrappedError := utils.WordWrap(friendlyError(msg.err), 72)
 		m.messages = append(m.messages, "Error: "+wrappedError)
 
 		// Add extra newline for better separation
@@ -333,7 +584,7 @@ func (m model) View() string {
 		helpStyle := lipgloss.NewStyle().Width(72)
 
 		// Split the help message into sections and format each separately
-		sections := strings.Split(helpMessage, "\n\n")
+		sections := strings.Split(helpMessage(), "\n\n")
 		formattedSections := make([]string, len(sections))
 
 		for i, section := range sections {
@@ -355,7 +606,7 @@ func (m model) View() string {
 
 	var status string
 	if m.processing {
-		status = m.spinner.View() + " Getting response..."
+		status = m.spinner.View() + " Getting response... (Ctrl+X to cancel)"
 	} else {
 		status = ""
 	}
@@ -363,9 +614,23 @@ func (m model) View() string {
 	// Add LangChain status indicator
 	var langchainStatus string
 	if m.llm != nil {
-		langchainStatus = "LangChain: Active ✓"
+		langchainStatus = fmt.Sprintf("LangChain: Active ✓ (%v)", m.llm)
 	} else {
-		langchainStatus = "LangChain: Inactive ✗ (Set OPENAI_API_KEY to enable)"
+		langchainStatus = "LangChain: Inactive ✗ (Set OPENAI_API_KEY or LLM_PROVIDER to enable)"
+	}
+
+	// Show the remaining JokeAPI budget once it is known
+	if quota := m.jokeClient.Quota(); quota.Known {
+		langchainStatus += " | JokeAPI quota: " + quota.String()
+	}
+
+	if m.safeMode {
+		langchainStatus += " | Safe mode"
+	}
+
+	// Warn while the circuit breaker is skipping JokeAPI
+	if state := m.jokeClient.BreakerState(); state != jokeclient.BreakerClosed {
+		langchainStatus += " | " + lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Render("Joke API degraded ("+state.String()+")")
 	}
 
 	return lipgloss.JoinVertical(
@@ -381,6 +646,13 @@ func (m model) View() string {
 }
 
 func main() {
+	check := flag.Bool("check", false, "check that JokeAPI is healthy, print a report and exit")
+	flag.Parse()
+
+	if *check {
+		os.Exit(runCheck())
+	}
+
 	p := tea.NewProgram(initialModel())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/AriT93/ai-agent/config"
+	"github.com/AriT93/ai-agent/jokeclient"
+	jokemodel "github.com/AriT93/ai-agent/model"
+)
+
+// runCommand handles a slash command such as "/joke 42"
+func (m model) runCommand(input string) (model, tea.Cmd) {
+	fields := strings.Fields(input)
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case "/joke":
+		if len(args) != 1 {
+			return m.addMessage("Usage: /joke <id>, e.g. /joke 42"), nil
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil || id < 0 {
+			return m.addMessage(fmt.Sprintf("Error: invalid joke ID: %q", args[0])), nil
+		}
+		return m.startRequest(func(ctx context.Context, reqID int) tea.Cmd {
+			return fetchQueryCmd(ctx, reqID, m.jokeClient, jokeclient.JokeQuery{IDRange: &jokeclient.IDRange{From: id, To: id}, SafeMode: m.safeMode})
+		})
+
+	case "/range":
+		if len(args) != 1 {
+			return m.addMessage("Usage: /range <from>-<to>, e.g. /range 10-50"), nil
+		}
+		idRange, err := jokeclient.ParseIDRange(args[0])
+		if err != nil {
+			return m.addMessage("Error: " + err.Error()), nil
+		}
+		return m.startRequest(func(ctx context.Context, reqID int) tea.Cmd {
+			return fetchQueryCmd(ctx, reqID, m.jokeClient, jokeclient.JokeQuery{IDRange: idRange, SafeMode: m.safeMode})
+		})
+
+	case "/safe":
+		return m.setSafeMode(args), nil
+
+	case "/status":
+		return m.startRequest(func(ctx context.Context, reqID int) tea.Cmd {
+			return statusCmd(ctx, reqID, m.jokeClient)
+		})
+
+	case "/submit":
+		return m.startSubmit(args), nil
+	}
+
+	return m.addMessage(fmt.Sprintf("Unknown command %s. Type 'help' to see the commands.", name)), nil
+}
+
+// setSafeMode handles "/safe on|off", saving the choice in the config file
+func (m model) setSafeMode(args []string) model {
+	if len(args) == 0 {
+		state := "off"
+		if m.safeMode {
+			state = "on"
+		}
+		return m.addMessage(fmt.Sprintf("Safe mode is %s. Usage: /safe on|off", state))
+	}
+
+	state := strings.ToLower(args[0])
+	var safeMode bool
+	switch state {
+	case "on":
+		safeMode = true
+	case "off":
+		safeMode = false
+	default:
+		return m.addMessage("Usage: /safe on|off")
+	}
+
+	// Requests already in flight keep the setting they were started with
+	m.safeMode = safeMode
+	if err := config.Update(func(cfg *config.Config) { cfg.SafeMode = safeMode }); err != nil {
+		return m.addMessage(fmt.Sprintf("Safe mode is %s for this session, but it could not be saved: %v", state, err))
+	}
+	return m.addMessage(fmt.Sprintf("Safe mode is %s.", state))
+}
+
+// fetchQueryCmd fetches a joke from JokeAPI exactly as queried, without the
+// LangChain parser or enhancer, so IDs shared in chat give the original text
+func fetchQueryCmd(ctx context.Context, id int, client *jokeclient.Client, query jokeclient.JokeQuery) tea.Cmd {
+	return func() tea.Msg {
+		response, err := client.FetchJokeWithQuery(ctx, query)
+		if err != nil {
+			return errorResponseMsg{id: id, err: err}
+		}
+		return newJokeResponse(id, jokemodel.JokeFromResponse(*response))
+	}
+}
+
+// addMessage appends a line to the chat and scrolls to it
+func (m model) addMessage(text string) model {
+	m.messages = append(m.messages, text)
+	m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
+	m.viewport.GotoBottom()
+	return m
+}
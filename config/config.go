@@ -0,0 +1,282 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Providers that can be selected as the joke source
+const (
+	ProviderJokeAPI  = "jokeapi"
+	ProviderDadJoke  = "dadjoke"
+	ProviderOfficial = "official"
+	ProviderLocal    = "local"
+)
+
+// Language model providers that can parse requests and enhance jokes
+const (
+	LLMOpenAI           = "openai"
+	LLMOllama           = "ollama"
+	LLMAnthropic        = "anthropic"
+	LLMMistral          = "mistral"
+	LLMOpenAICompatible = "openai-compatible" // Any server speaking the OpenAI API, such as llama.cpp
+	LLMNone             = "none"
+)
+
+// Config holds the application settings. Values are read from the config
+// file first and can be overridden by environment variables.
+type Config struct {
+	Provider        string   `json:"provider"`        // Joke source, one of the Provider constants
+	Providers       []string `json:"providers"`       // Joke sources to try in order, overriding Provider and Fallback
+	ProviderTimeout Duration `json:"providerTimeout"` // How long each source in the chain gets to answer
+	CorpusPath      string   `json:"corpusPath"`      // Local joke corpus file, the built-in corpus if empty
+	Fallback        bool     `json:"fallback"`        // Use the local corpus when the provider fails
+	Cache           string   `json:"cache"`           // Response cache, "memory" or "disk"
+	CacheTTL        Duration `json:"cacheTTL"`        // How long cached responses stay fresh
+	SafeMode        bool     `json:"safeMode"`        // Only show jokes suitable for everyone
+	PolicyPath      string   `json:"policyPath"`      // Content policy file applied to every joke, none if empty
+	Debug           bool     `json:"debug"`           // Write the joke API debug log
+	LLM             LLM      `json:"llm"`             // Language model for parsing and enhancing
+}
+
+// LLM selects the language model and how it samples
+type LLM struct {
+	Provider    string   `json:"provider"`              // One of the LLM constants, empty for OpenAI when OPENAI_API_KEY is set
+	Model       string   `json:"model"`                 // Model name, the provider's default if empty
+	BaseURL     string   `json:"baseURL"`               // Server URL, required for openai-compatible
+	Temperature *float64 `json:"temperature,omitempty"` // Sampling temperature, the provider's default if unset
+	MaxTokens   int      `json:"maxTokens"`             // Longest reply, the provider's default if zero
+	APIKey      string   `json:"-"`                     // From LLM_API_KEY, never saved to the config file
+}
+
+// Duration is a time.Duration written as a string such as "10m" in the config file
+type Duration time.Duration
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON reads a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Default returns the settings used when nothing is configured
+func Default() Config {
+	return Config{
+		Provider:        ProviderJokeAPI,
+		ProviderTimeout: Duration(8 * time.Second),
+		Fallback:        true,
+		Cache:           "memory",
+		CacheTTL:        Duration(10 * time.Minute),
+	}
+}
+
+// ProviderOrder returns the joke sources to try, in order. Providers is used
+// when set; otherwise Provider is followed by the local corpus if Fallback is on.
+func (c Config) ProviderOrder() []string {
+	if len(c.Providers) > 0 {
+		return c.Providers
+	}
+	if c.Fallback && c.Provider != ProviderLocal {
+		return []string{c.Provider, ProviderLocal}
+	}
+	return []string{c.Provider}
+}
+
+// Path returns the location of the config file under the user config directory
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ai-agent", "config.json"), nil
+}
+
+// Load reads the config file, if there is one, and applies environment overrides
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		cfg := Default()
+		cfg.applyEnv()
+		return cfg, nil
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads settings from path, if it exists, and applies environment overrides
+func LoadFile(path string) (Config, error) {
+	cfg, err := readFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg.applyEnv()
+	return cfg, cfg.Validate()
+}
+
+// Update changes settings in the config file, creating it if needed
+func Update(change func(*Config)) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return UpdateFile(path, change)
+}
+
+// UpdateFile changes settings in the config file at path, creating it if
+// needed. Environment overrides are not written to the file.
+func UpdateFile(path string, change func(*Config)) error {
+	cfg, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	change(&cfg)
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// readFile reads settings from path over the defaults, without environment overrides
+func readFile(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that the settings name known options
+func (c Config) Validate() error {
+	for _, provider := range append([]string{c.Provider}, c.Providers...) {
+		switch provider {
+		case ProviderJokeAPI, ProviderDadJoke, ProviderOfficial, ProviderLocal:
+		default:
+			return fmt.Errorf("unknown joke provider: %s", provider)
+		}
+	}
+
+	switch c.Cache {
+	case "memory", "disk":
+	default:
+		return fmt.Errorf("unknown cache: %s", c.Cache)
+	}
+
+	return c.LLM.Validate()
+}
+
+// Validate checks the language model settings
+func (l LLM) Validate() error {
+	switch l.Provider {
+	case "", LLMOpenAI, LLMOllama, LLMAnthropic, LLMMistral, LLMNone:
+	case LLMOpenAICompatible:
+		if l.BaseURL == "" {
+			return fmt.Errorf("the %s LLM provider needs a base URL", l.Provider)
+		}
+	default:
+		return fmt.Errorf("unknown LLM provider: %s", l.Provider)
+	}
+
+	if l.Temperature != nil && (*l.Temperature < 0 || *l.Temperature > 2) {
+		return fmt.Errorf("LLM temperature must be between 0 and 2, got %v", *l.Temperature)
+	}
+	if l.MaxTokens < 0 {
+		return fmt.Errorf("LLM max tokens must not be negative, got %d", l.MaxTokens)
+	}
+	return nil
+}
+
+// applyEnv overrides settings from environment variables
+func (c *Config) applyEnv() {
+	if value := os.Getenv("JOKE_PROVIDER"); value != "" {
+		c.Provider = value
+	}
+	if value := os.Getenv("JOKE_PROVIDERS"); value != "" {
+		c.Providers = nil
+		for _, provider := range strings.Split(value, ",") {
+			if provider = strings.TrimSpace(provider); provider != "" {
+				c.Providers = append(c.Providers, provider)
+			}
+		}
+	}
+	if value, err := time.ParseDuration(os.Getenv("JOKE_PROVIDER_TIMEOUT")); err == nil {
+		c.ProviderTimeout = Duration(value)
+	}
+	if value := os.Getenv("JOKE_CORPUS"); value != "" {
+		c.CorpusPath = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("JOKE_FALLBACK")); err == nil {
+		c.Fallback = value
+	}
+	if value := os.Getenv("JOKE_CACHE"); value != "" {
+		c.Cache = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("JOKE_CACHE_TTL")); err == nil {
+		c.CacheTTL = Duration(value)
+	}
+	if value := os.Getenv("JOKE_POLICY"); value != "" {
+		c.PolicyPath = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("JOKE_SAFE_MODE")); err == nil {
+		c.SafeMode = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("DEBUG")); err == nil {
+		c.Debug = value
+	}
+	if value := os.Getenv("LLM_PROVIDER"); value != "" {
+		c.LLM.Provider = value
+	}
+	if value := os.Getenv("LLM_MODEL"); value != "" {
+		c.LLM.Model = value
+	}
+	if value := os.Getenv("LLM_BASE_URL"); value != "" {
+		c.LLM.BaseURL = value
+	}
+	if value, err := strconv.ParseFloat(os.Getenv("LLM_TEMPERATURE"), 64); err == nil {
+		c.LLM.Temperature = &value
+	}
+	if value, err := strconv.Atoi(os.Getenv("LLM_MAX_TOKENS")); err == nil {
+		c.LLM.MaxTokens = value
+	}
+	if value := os.Getenv("LLM_API_KEY"); value != "" {
+		c.LLM.APIKey = value
+	}
+}
@@ -0,0 +1,252 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/config"
+)
+
+var _ = Describe("Config", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "config.json")
+		for _, name := range []string{"JOKE_PROVIDER", "JOKE_PROVIDERS", "JOKE_PROVIDER_TIMEOUT", "JOKE_CORPUS", "JOKE_FALLBACK", "JOKE_CACHE", "JOKE_CACHE_TTL", "JOKE_SAFE_MODE", "JOKE_POLICY", "DEBUG", "LLM_PROVIDER", "LLM_MODEL", "LLM_BASE_URL", "LLM_TEMPERATURE", "LLM_MAX_TOKENS", "LLM_API_KEY"} {
+			GinkgoT().Setenv(name, "")
+		}
+	})
+
+	It("should use the defaults when there is no config file", func() {
+		cfg, err := config.LoadFile(path)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg).To(Equal(config.Default()))
+		Expect(cfg.Provider).To(Equal(config.ProviderJokeAPI))
+		Expect(cfg.Fallback).To(BeTrue())
+	})
+
+	It("should read settings from the config file", func() {
+		Expect(os.WriteFile(path, []byte(`{
+			"provider": "local",
+			"corpusPath": "/srv/jokes.yaml",
+			"fallback": false,
+			"cacheTTL": "30m"
+		}`), 0644)).To(Succeed())
+
+		cfg, err := config.LoadFile(path)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Provider).To(Equal(config.ProviderLocal))
+		Expect(cfg.CorpusPath).To(Equal("/srv/jokes.yaml"))
+		Expect(cfg.Fallback).To(BeFalse())
+		Expect(cfg.Cache).To(Equal("memory"))
+		Expect(time.Duration(cfg.CacheTTL)).To(Equal(30 * time.Minute))
+	})
+
+	It("should let environment variables override the config file", func() {
+		Expect(os.WriteFile(path, []byte(`{"provider": "official"}`), 0644)).To(Succeed())
+		GinkgoT().Setenv("JOKE_PROVIDER", "dadjoke")
+		GinkgoT().Setenv("JOKE_FALLBACK", "false")
+		GinkgoT().Setenv("DEBUG", "true")
+
+		cfg, err := config.LoadFile(path)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Provider).To(Equal(config.ProviderDadJoke))
+		Expect(cfg.Fallback).To(BeFalse())
+		Expect(cfg.Debug).To(BeTrue())
+	})
+
+	It("should reject unknown providers", func() {
+		GinkgoT().Setenv("JOKE_PROVIDER", "jokes4u")
+
+		_, err := config.LoadFile(path)
+
+		Expect(err).To(MatchError(ContainSubstring("unknown joke provider")))
+	})
+
+	It("should read the provider order from the environment", func() {
+		GinkgoT().Setenv("JOKE_PROVIDERS", "jokeapi, official,local")
+		GinkgoT().Setenv("JOKE_PROVIDER_TIMEOUT", "3s")
+
+		cfg, err := config.LoadFile(path)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.ProviderOrder()).To(Equal([]string{"jokeapi", "official", "local"}))
+		Expect(time.Duration(cfg.ProviderTimeout)).To(Equal(3 * time.Second))
+	})
+
+	It("should reject unknown providers in the provider order", func() {
+		Expect(os.WriteFile(path, []byte(`{"providers": ["jokeapi", "jokes4u"]}`), 0644)).To(Succeed())
+
+		_, err := config.LoadFile(path)
+
+		Expect(err).To(MatchError(ContainSubstring("unknown joke provider: jokes4u")))
+	})
+
+	It("should read safe mode from the config file and environment", func() {
+		Expect(os.WriteFile(path, []byte(`{"safeMode": true}`), 0644)).To(Succeed())
+
+		cfg, err := config.LoadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.SafeMode).To(BeTrue())
+
+		GinkgoT().Setenv("JOKE_SAFE_MODE", "false")
+		cfg, err = config.LoadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.SafeMode).To(BeFalse())
+	})
+
+	It("should read the policy path from the environment", func() {
+		GinkgoT().Setenv("JOKE_POLICY", "/etc/ai-agent/policy.yaml")
+
+		cfg, err := config.LoadFile(path)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.PolicyPath).To(Equal("/etc/ai-agent/policy.yaml"))
+	})
+
+	Describe("LLM", func() {
+		It("should read the language model from the config file", func() {
+			Expect(os.WriteFile(path, []byte(`{
+				"llm": {"provider": "ollama", "model": "llama3.2", "baseURL": "http://gpu-box:11434", "temperature": 0.2, "maxTokens": 256}
+			}`), 0644)).To(Succeed())
+
+			cfg, err := config.LoadFile(path)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.LLM.Provider).To(Equal(config.LLMOllama))
+			Expect(cfg.LLM.Model).To(Equal("llama3.2"))
+			Expect(cfg.LLM.BaseURL).To(Equal("http://gpu-box:11434"))
+			Expect(cfg.LLM.Temperature).To(HaveValue(Equal(0.2)))
+			Expect(cfg.LLM.MaxTokens).To(Equal(256))
+		})
+
+		It("should leave the temperature to the provider unless it is set", func() {
+			cfg, err := config.LoadFile(path)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.LLM.Temperature).To(BeNil())
+		})
+
+		It("should let environment variables override the config file", func() {
+			Expect(os.WriteFile(path, []byte(`{"llm": {"provider": "openai", "model": "gpt-4o-mini"}}`), 0644)).To(Succeed())
+			GinkgoT().Setenv("LLM_PROVIDER", "openai-compatible")
+			GinkgoT().Setenv("LLM_MODEL", "qwen2.5")
+			GinkgoT().Setenv("LLM_BASE_URL", "http://localhost:8080/v1")
+			GinkgoT().Setenv("LLM_TEMPERATURE", "0")
+			GinkgoT().Setenv("LLM_MAX_TOKENS", "512")
+			GinkgoT().Setenv("LLM_API_KEY", "secret")
+
+			cfg, err := config.LoadFile(path)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.LLM).To(Equal(config.LLM{
+				Provider:    config.LLMOpenAICompatible,
+				Model:       "qwen2.5",
+				BaseURL:     "http://localhost:8080/v1",
+				Temperature: cfg.LLM.Temperature,
+				MaxTokens:   512,
+				APIKey:      "secret",
+			}))
+			Expect(cfg.LLM.Temperature).To(HaveValue(BeZero()))
+		})
+
+		DescribeTable("should reject invalid settings",
+			func(contents, message string) {
+				Expect(os.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+
+				_, err := config.LoadFile(path)
+
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("unknown provider", `{"llm": {"provider": "skynet"}}`, "unknown LLM provider: skynet"),
+			Entry("compatible server without a URL", `{"llm": {"provider": "openai-compatible"}}`, "needs a base URL"),
+			Entry("temperature out of range", `{"llm": {"provider": "ollama", "temperature": 3}}`, "temperature must be between 0 and 2"),
+			Entry("negative max tokens", `{"llm": {"provider": "ollama", "maxTokens": -1}}`, "must not be negative"),
+		)
+
+		It("should never save the API key", func() {
+			GinkgoT().Setenv("LLM_API_KEY", "secret")
+			Expect(config.UpdateFile(path, func(cfg *config.Config) {
+				cfg.LLM.Provider = config.LLMMistral
+				cfg.LLM.APIKey = "secret"
+			})).To(Succeed())
+
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"provider": "mistral"`))
+			Expect(string(data)).NotTo(ContainSubstring("secret"))
+		})
+	})
+
+	Describe("UpdateFile", func() {
+		It("should create the config file with the change", func() {
+			path = filepath.Join(GinkgoT().TempDir(), "ai-agent", "config.json")
+
+			Expect(config.UpdateFile(path, func(cfg *config.Config) { cfg.SafeMode = true })).To(Succeed())
+
+			cfg, err := config.LoadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.SafeMode).To(BeTrue())
+			Expect(cfg.Provider).To(Equal(config.ProviderJokeAPI))
+		})
+
+		It("should keep existing settings and leave environment overrides out", func() {
+			Expect(os.WriteFile(path, []byte(`{"provider": "official", "cacheTTL": "30m"}`), 0644)).To(Succeed())
+			GinkgoT().Setenv("JOKE_PROVIDER", "dadjoke")
+
+			Expect(config.UpdateFile(path, func(cfg *config.Config) { cfg.SafeMode = true })).To(Succeed())
+
+			GinkgoT().Setenv("JOKE_PROVIDER", "")
+			cfg, err := config.LoadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Provider).To(Equal(config.ProviderOfficial))
+			Expect(time.Duration(cfg.CacheTTL)).To(Equal(30 * time.Minute))
+			Expect(cfg.SafeMode).To(BeTrue())
+		})
+
+		It("should refuse to save invalid settings", func() {
+			err := config.UpdateFile(path, func(cfg *config.Config) { cfg.Cache = "redis" })
+
+			Expect(err).To(MatchError(ContainSubstring("unknown cache")))
+			Expect(path).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("ProviderOrder", func() {
+		It("should follow the provider with the local corpus when fallback is on", func() {
+			cfg := config.Default()
+			cfg.Provider = config.ProviderDadJoke
+
+			Expect(cfg.ProviderOrder()).To(Equal([]string{"dadjoke", "local"}))
+		})
+
+		It("should use the provider alone when fallback is off", func() {
+			cfg := config.Default()
+			cfg.Fallback = false
+
+			Expect(cfg.ProviderOrder()).To(Equal([]string{"jokeapi"}))
+		})
+
+		It("should not add the local corpus twice", func() {
+			cfg := config.Default()
+			cfg.Provider = config.ProviderLocal
+
+			Expect(cfg.ProviderOrder()).To(Equal([]string{"local"}))
+		})
+	})
+
+	It("should report malformed config files", func() {
+		Expect(os.WriteFile(path, []byte(`{"provider": `), 0644)).To(Succeed())
+
+		_, err := config.LoadFile(path)
+
+		Expect(err).To(MatchError(ContainSubstring("failed to parse config")))
+	})
+})
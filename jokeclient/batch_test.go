@@ -0,0 +1,144 @@
+package jokeclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("FetchJokes", func() {
+	var (
+		client    *jokeclient.Client
+		server    *httptest.Server
+		amount    string
+		batches   int
+		sameBatch bool
+	)
+
+	BeforeEach(func() {
+		amount = ""
+		batches = 0
+		sameBatch = false
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			amount = r.URL.Query().Get("amount")
+			w.Header().Set("Content-Type", "application/json")
+
+			if amount == "" {
+				w.Write([]byte(`{
+					"error": false,
+					"category": "Pun",
+					"type": "single",
+					"joke": "Only joke",
+					"flags": {},
+					"id": 1,
+					"safe": true,
+					"lang": "en"
+				}`))
+				return
+			}
+
+			// Each batch has new IDs unless sameBatch is set
+			batches++
+			first := 1
+			if !sameBatch {
+				first += 3 * (batches - 1)
+			}
+			fmt.Fprintf(w, `{
+				"error": false,
+				"amount": 3,
+				"jokes": [
+					{"category": "Pun", "type": "single", "joke": "First", "flags": {}, "id": %d, "safe": true, "lang": "en"},
+					{"category": "Pun", "type": "twopart", "setup": "Second", "delivery": "joke", "flags": {}, "id": %d, "safe": true, "lang": "en"},
+					{"category": "Pun", "type": "single", "joke": "Third", "flags": {}, "id": %d, "safe": true, "lang": "en"}
+				]
+			}`, first, first+1, first+2)
+		}))
+
+		client = jokeclient.NewClient()
+		client.BaseURL = server.URL
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should request and decode a batch of jokes", func() {
+		jokes, err := client.FetchJokes(context.Background(), jokeclient.JokeQuery{
+			Categories: []string{"pun"},
+			Amount:     3,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amount).To(Equal("3"))
+		Expect(jokes).To(HaveLen(3))
+		Expect(jokes[0].Joke).To(Equal("First"))
+		Expect(jokeclient.FormatJoke(&jokes[1])).To(Equal("Second\n\njoke"))
+	})
+
+	It("should fall back to a single joke for small amounts", func() {
+		jokes, err := client.FetchJokes(context.Background(), jokeclient.JokeQuery{Amount: 1})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amount).To(BeEmpty())
+		Expect(jokes).To(HaveLen(1))
+		Expect(jokes[0].Joke).To(Equal("Only joke"))
+	})
+
+	// ids returns the IDs of jokes
+	ids := func(jokes []model.JokeResponse) []int {
+		result := make([]int, len(jokes))
+		for i, joke := range jokes {
+			result[i] = joke.ID
+		}
+		return result
+	}
+
+	It("should serve a repeated batch from the cache", func() {
+		client.Cache = jokeclient.NewMemoryCache(10)
+		query := jokeclient.JokeQuery{Categories: []string{"pun"}, Amount: 3}
+
+		first, err := client.FetchJokes(context.Background(), query)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := client.FetchJokes(context.Background(), query)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ids(second)).To(Equal(ids(first)))
+		Expect(batches).To(Equal(1))
+	})
+
+	It("should not repeat a batch in no repeats mode", func() {
+		client.Cache = jokeclient.NewMemoryCache(10)
+		client.NoRepeats = true
+		query := jokeclient.JokeQuery{Categories: []string{"pun"}, Amount: 3}
+
+		first, err := client.FetchJokes(context.Background(), query)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := client.FetchJokes(context.Background(), query)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ids(first)).To(Equal([]int{1, 2, 3}))
+		Expect(ids(second)).To(Equal([]int{4, 5, 6}))
+		Expect(batches).To(Equal(2))
+	})
+
+	It("should refetch a batch of jokes already shown, then give up", func() {
+		client.NoRepeats = true
+		sameBatch = true
+		query := jokeclient.JokeQuery{Amount: 3}
+
+		_, err := client.FetchJokes(context.Background(), query)
+		Expect(err).NotTo(HaveOccurred())
+		jokes, err := client.FetchJokes(context.Background(), query)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ids(jokes)).To(Equal([]int{1, 2, 3}))
+		Expect(batches).To(Equal(4))
+	})
+})
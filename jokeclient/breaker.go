@@ -0,0 +1,135 @@
+package jokeclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+// Circuit breaker states
+const (
+	BreakerClosed   BreakerState = iota // Requests flow normally
+	BreakerOpen                         // Requests fail fast until the cooldown passes
+	BreakerHalfOpen                     // A single probe request is allowed through
+)
+
+// String names the state for display
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// CircuitBreaker stops calling a failing service. It opens after
+// FailureThreshold consecutive failures, fails fast for Cooldown, then lets a
+// single probe through: success closes it again, failure reopens it. It is
+// safe for concurrent use.
+type CircuitBreaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a request may proceed. Every allowed request must be
+// followed by a call to Record with its outcome, or Skip if it has none.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+
+	switch b.state {
+	case BreakerOpen:
+		retryIn := b.Cooldown - b.now().Sub(b.openedAt)
+		return fmt.Errorf("%w, retrying in %v", ErrCircuitOpen, retryIn.Round(time.Second))
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w, probe in progress", ErrCircuitOpen)
+		}
+		b.probing = true
+	}
+
+	return nil
+}
+
+// Record reports the outcome of an allowed request. Errors that show the
+// service is answering, such as a search without results, should be recorded
+// as nil.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Skip releases an allowed request that ended without saying anything about
+// the service's health, such as one cancelled by the caller
+func (b *CircuitBreaker) Skip() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	return b.state
+}
+
+// advance moves an open breaker to half-open once the cooldown has passed
+func (b *CircuitBreaker) advance() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.Cooldown {
+		b.state = BreakerHalfOpen
+		b.probing = false
+	}
+}
+
+// isOutage reports whether err means the service is unhealthy, as opposed to
+// the request being unanswerable or cancelled
+func isOutage(err error) bool {
+	return err != nil && retryable(err)
+}
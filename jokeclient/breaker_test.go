@@ -0,0 +1,162 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	var (
+		breaker *jokeclient.CircuitBreaker
+		failure = errors.New("service unavailable")
+	)
+
+	BeforeEach(func() {
+		breaker = jokeclient.NewCircuitBreaker(2, 50*time.Millisecond)
+	})
+
+	It("should stay closed below the failure threshold", func() {
+		Expect(breaker.Allow()).To(Succeed())
+		breaker.Record(failure)
+
+		Expect(breaker.State()).To(Equal(jokeclient.BreakerClosed))
+		Expect(breaker.Allow()).To(Succeed())
+	})
+
+	It("should reset the failure count after a success", func() {
+		breaker.Record(failure)
+		breaker.Record(nil)
+		breaker.Record(failure)
+
+		Expect(breaker.State()).To(Equal(jokeclient.BreakerClosed))
+	})
+
+	It("should open and fail fast after consecutive failures", func() {
+		breaker.Record(failure)
+		breaker.Record(failure)
+
+		Expect(breaker.State()).To(Equal(jokeclient.BreakerOpen))
+		Expect(breaker.Allow()).To(MatchError(jokeclient.ErrCircuitOpen))
+	})
+
+	It("should allow a single probe once the cooldown has passed", func() {
+		breaker.Record(failure)
+		breaker.Record(failure)
+
+		Eventually(breaker.State).Should(Equal(jokeclient.BreakerHalfOpen))
+		Expect(breaker.Allow()).To(Succeed())
+		Expect(breaker.Allow()).To(MatchError(jokeclient.ErrCircuitOpen))
+	})
+
+	It("should close when the probe succeeds", func() {
+		breaker.Record(failure)
+		breaker.Record(failure)
+		Eventually(breaker.State).Should(Equal(jokeclient.BreakerHalfOpen))
+
+		Expect(breaker.Allow()).To(Succeed())
+		breaker.Record(nil)
+
+		Expect(breaker.State()).To(Equal(jokeclient.BreakerClosed))
+		Expect(breaker.Allow()).To(Succeed())
+	})
+
+	It("should reopen when the probe fails", func() {
+		breaker.Record(failure)
+		breaker.Record(failure)
+		Eventually(breaker.State).Should(Equal(jokeclient.BreakerHalfOpen))
+
+		Expect(breaker.Allow()).To(Succeed())
+		breaker.Record(failure)
+
+		Expect(breaker.State()).To(Equal(jokeclient.BreakerOpen))
+	})
+
+	It("should stay half-open when the probe is skipped", func() {
+		breaker.Record(failure)
+		breaker.Record(failure)
+		Eventually(breaker.State).Should(Equal(jokeclient.BreakerHalfOpen))
+
+		Expect(breaker.Allow()).To(Succeed())
+		breaker.Skip()
+
+		Expect(breaker.State()).To(Equal(jokeclient.BreakerHalfOpen))
+		Expect(breaker.Allow()).To(Succeed())
+	})
+})
+
+var _ = Describe("Client with a circuit breaker", func() {
+	var (
+		server   *httptest.Server
+		requests atomic.Int32
+		status   atomic.Int32
+		client   *jokeclient.Client
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		status.Store(http.StatusServiceUnavailable)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(int(status.Load()))
+			w.Write([]byte(`{"error": false, "category": "Misc", "type": "single", "joke": "Back up", "flags": {}, "id": 1, "safe": true, "lang": "en"}`))
+		}))
+		client = jokeclient.NewClient(
+			jokeclient.WithBaseURL(server.URL),
+			jokeclient.WithCircuitBreaker(jokeclient.NewCircuitBreaker(2, 50*time.Millisecond)),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should report a closed breaker when none is configured", func() {
+		Expect(jokeclient.NewClient().BreakerState()).To(Equal(jokeclient.BreakerClosed))
+	})
+
+	It("should fail fast without calling the API once the breaker opens", func() {
+		for i := 0; i < 2; i++ {
+			_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).To(HaveOccurred())
+		}
+		Expect(client.BreakerState()).To(Equal(jokeclient.BreakerOpen))
+
+		_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).To(MatchError(jokeclient.ErrCircuitOpen))
+		Expect(requests.Load()).To(Equal(int32(2)))
+	})
+
+	It("should recover once a half-open probe succeeds", func() {
+		for i := 0; i < 2; i++ {
+			client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+		}
+		status.Store(http.StatusOK)
+		Eventually(client.BreakerState).Should(Equal(jokeclient.BreakerHalfOpen))
+
+		joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Joke).To(Equal("Back up"))
+		Expect(client.BreakerState()).To(Equal(jokeclient.BreakerClosed))
+	})
+
+	It("should not count API errors for unanswerable queries as outages", func() {
+		status.Store(http.StatusBadRequest)
+
+		for i := 0; i < 3; i++ {
+			client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+		}
+
+		Expect(client.BreakerState()).To(Equal(jokeclient.BreakerClosed))
+	})
+})
@@ -0,0 +1,228 @@
+package jokeclient
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached responses stay fresh unless configured otherwise
+const DefaultCacheTTL = 10 * time.Minute
+
+// Cache stores raw API responses keyed by normalized query
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// cacheKey returns a key that is identical for queries asking for the same jokes
+func (q JokeQuery) cacheKey() string {
+	categories := slices.Clone(q.Categories)
+	slices.Sort(categories)
+	flags := slices.Clone(q.BlacklistFlags)
+	slices.Sort(flags)
+
+	idRange := ""
+	if q.IDRange != nil {
+		idRange = q.IDRange.String()
+	}
+
+	return strings.Join([]string{
+		"category=" + strings.Join(categories, ","),
+		"type=" + q.Type,
+		"flags=" + strings.Join(flags, ","),
+		"lang=" + q.Language,
+		"contains=" + strings.ToLower(q.Contains),
+		"idRange=" + idRange,
+		"amount=" + strconv.Itoa(q.Amount),
+		"safe=" + strconv.FormatBool(q.SafeMode),
+	}, "&")
+}
+
+// cacheEntry is a cached response and its expiry time
+type cacheEntry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// MemoryCache is an in-memory least recently used cache. It is safe for
+// concurrent use.
+type MemoryCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+}
+
+// NewMemoryCache creates an LRU cache holding at most capacity responses
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached response for key if it has not expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.Expires) {
+		m.order.Remove(element)
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	m.order.MoveToFront(element)
+	return entry.Body, true
+}
+
+// Set stores a response for key, evicting the least recently used entry when full
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &cacheEntry{Key: key, Expires: time.Now().Add(ttl), Body: value}
+
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*cacheEntry).Key)
+	}
+}
+
+// Len returns the number of cached responses, including expired ones not yet evicted
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// DiskCache stores responses as files in a directory so they survive restarts
+type DiskCache struct {
+	Dir string
+}
+
+// DefaultCacheDir returns the jokes cache directory under the user cache directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ai-agent", "jokes"), nil
+}
+
+// NewDiskCache creates a disk cache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+// path returns the file a key is stored in
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the cached response for key if it has not expired
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+
+	if time.Now().After(entry.Expires) {
+		os.Remove(d.path(key))
+		return nil, false
+	}
+
+	return entry.Body, true
+}
+
+// Set stores a response for key. Write errors are ignored since the cache is
+// only an optimisation.
+func (d *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	data, err := json.Marshal(cacheEntry{Key: key, Expires: time.Now().Add(ttl), Body: value})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(d.Dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// seenJokes tracks the IDs of jokes already shown in this session
+type seenJokes struct {
+	mu  sync.Mutex
+	ids map[int]bool
+}
+
+// markSeen records id and reports whether it had been seen before
+func (s *seenJokes) markSeen(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ids == nil {
+		s.ids = make(map[int]bool)
+	}
+	if s.ids[id] {
+		return true
+	}
+	s.ids[id] = true
+	return false
+}
+
+// reset forgets every joke seen so far
+func (s *seenJokes) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = nil
+}
@@ -0,0 +1,174 @@
+package jokeclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("Response caching", func() {
+	Describe("MemoryCache", func() {
+		It("should return stored responses until they expire", func() {
+			cache := jokeclient.NewMemoryCache(10)
+			cache.Set("fresh", []byte("a"), time.Minute)
+			cache.Set("stale", []byte("b"), -time.Second)
+
+			value, ok := cache.Get("fresh")
+			Expect(ok).To(BeTrue())
+			Expect(string(value)).To(Equal("a"))
+
+			_, ok = cache.Get("stale")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should evict the least recently used entry when full", func() {
+			cache := jokeclient.NewMemoryCache(2)
+			cache.Set("one", []byte("1"), time.Minute)
+			cache.Set("two", []byte("2"), time.Minute)
+			cache.Get("one")
+			cache.Set("three", []byte("3"), time.Minute)
+
+			Expect(cache.Len()).To(Equal(2))
+			_, ok := cache.Get("two")
+			Expect(ok).To(BeFalse())
+			_, ok = cache.Get("one")
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	Describe("DiskCache", func() {
+		It("should persist responses across instances", func() {
+			dir := GinkgoT().TempDir()
+
+			first, err := jokeclient.NewDiskCache(dir)
+			Expect(err).NotTo(HaveOccurred())
+			first.Set("category=Pun", []byte(`{"id":1}`), time.Minute)
+
+			second, err := jokeclient.NewDiskCache(dir)
+			Expect(err).NotTo(HaveOccurred())
+			value, ok := second.Get("category=Pun")
+			Expect(ok).To(BeTrue())
+			Expect(string(value)).To(Equal(`{"id":1}`))
+		})
+
+		It("should drop expired responses", func() {
+			cache, err := jokeclient.NewDiskCache(GinkgoT().TempDir())
+			Expect(err).NotTo(HaveOccurred())
+			cache.Set("category=Pun", []byte(`{"id":1}`), -time.Second)
+
+			_, ok := cache.Get("category=Pun")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Client", func() {
+		var (
+			server   *httptest.Server
+			requests atomic.Int32
+			nextID   atomic.Int32
+			sameID   bool
+		)
+
+		BeforeEach(func() {
+			requests.Store(0)
+			nextID.Store(0)
+			sameID = false
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				id := nextID.Add(1)
+				if sameID {
+					id = 1
+				}
+				fmt.Fprintf(w, `{"error": false, "category": "Pun", "type": "single", "joke": "Joke %d", "flags": {}, "id": %d, "safe": true, "lang": "en"}`, id, id)
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should serve repeated queries from the cache", func() {
+			client := jokeclient.NewClient(
+				jokeclient.WithBaseURL(server.URL),
+				jokeclient.WithCache(jokeclient.NewMemoryCache(10), time.Minute),
+			)
+
+			first, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{
+				Categories:     []string{"pun", "programming"},
+				BlacklistFlags: []string{"nsfw", "racist"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Same query in a different order and casing
+			second, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{
+				Categories:     []string{"Programming", "Pun"},
+				BlacklistFlags: []string{"racist", "NSFW"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(second.ID).To(Equal(first.ID))
+			Expect(requests.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("should refetch jokes already shown in no repeats mode", func() {
+			client := jokeclient.NewClient(
+				jokeclient.WithBaseURL(server.URL),
+				jokeclient.WithCache(jokeclient.NewMemoryCache(10), time.Minute),
+				jokeclient.WithNoRepeats(true),
+			)
+
+			first, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			second, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(second.ID).NotTo(Equal(first.ID))
+			Expect(requests.Load()).To(BeEquivalentTo(2))
+		})
+
+		It("should give up refetching when the API keeps repeating itself", func() {
+			sameID = true
+			client := jokeclient.NewClient(
+				jokeclient.WithBaseURL(server.URL),
+				jokeclient.WithNoRepeats(true),
+			)
+
+			_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(joke.ID).To(Equal(1))
+			Expect(requests.Load()).To(BeEquivalentTo(4))
+
+			client.ResetSeen()
+			_, err = client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests.Load()).To(BeEquivalentTo(5))
+		})
+
+		It("should return a joke asked for by ID even if it was shown before", func() {
+			sameID = true
+			client := jokeclient.NewClient(
+				jokeclient.WithBaseURL(server.URL),
+				jokeclient.WithNoRepeats(true),
+			)
+
+			_, err := client.FetchJokeByID(context.Background(), 1)
+			Expect(err).NotTo(HaveOccurred())
+			joke, err := client.FetchJokeByID(context.Background(), 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(joke.ID).To(Equal(1))
+			Expect(requests.Load()).To(BeEquivalentTo(2))
+		})
+	})
+})
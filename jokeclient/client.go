@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AriT93/ai-agent/model"
@@ -22,29 +24,51 @@ const (
 
 // Client represents a joke API client
 type Client struct {
-	BaseURL   string
-	Timeout   time.Duration
-	Debug     bool   // Debug flag
-	DebugFile string // File to write debug output to
+	BaseURL    string
+	Timeout    time.Duration
+	Debug      bool            // Debug flag
+	DebugFile  string          // File to write debug output to
+	Retry      RetryPolicy     // Retry policy, no retries by default
+	Limiter    *RateLimiter    // Paces requests by the API's RateLimit headers, nil to disable
+	Breaker    *CircuitBreaker // Fails fast while the API is down, nil to disable
+	HTTPClient *http.Client    // Shared HTTP client, reused across requests
+	UserAgent  string          // User-Agent header sent with every request
+	Cache      Cache           // Response cache, nil to disable
+	CacheTTL   time.Duration   // How long cached responses stay fresh
+	NoRepeats  bool            // Refetch jokes that were already returned this session
+	Language   string          // Language for queries that do not name one, empty for the API default
+	SafeMode   bool            // Ask for and only accept jokes suitable for everyone
+	Policy     *Policy         // Content rules returned jokes must satisfy, nil for the query's own
+
+	seen      seenJokes
+	metaOnce  sync.Once
+	metaCache *MemoryCache // Metadata cache for clients without a Cache
 }
 
-// NewClient creates a new joke API client
-func NewClient(debug ...bool) *Client {
-	// Default debug to false, but allow it to be enabled via optional parameter
-	isDebug := false
-	if len(debug) > 0 {
-		isDebug = debug[0]
-	}
+// maxRefetches bounds how often a repeated or rejected joke is refetched
+const maxRefetches = 3
+
+// ErrPolicyViolation is returned when the API keeps serving jokes the
+// query's blacklist, safe mode or content policy reject
+var ErrPolicyViolation = errors.New("joke violates content policy")
 
+// NewClient creates a new joke API client configured by the given options
+func NewClient(opts ...Option) *Client {
 	client := &Client{
-		BaseURL:   "https://v2.jokeapi.dev/joke",
-		Timeout:   5 * time.Second,
-		Debug:     isDebug,
-		DebugFile: "joke_api_debug.log",
+		BaseURL:    "https://v2.jokeapi.dev/joke",
+		Timeout:    5 * time.Second,
+		DebugFile:  "joke_api_debug.log",
+		HTTPClient: newHTTPClient(),
+		UserAgent:  DefaultUserAgent,
+		CacheTTL:   DefaultCacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(client)
 	}
 
 	// Initialize debug writer if debug is enabled
-	if isDebug {
+	if client.Debug {
 		// Create or truncate debug file
 		file, err := os.OpenFile(client.DebugFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 		if err == nil {
@@ -101,108 +125,437 @@ func (c *Client) WriteDebugSeparator(title string) {
 	c.writeDebug("\n%s=== %s ===%s\n", boldText, title, resetColor)
 }
 
-// FetchJoke fetches a joke from the API based on the given parameters
+// httpClient returns the client's HTTP client, falling back to the default
+// one for clients that were not created with NewClient
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Quota returns the request budget last reported by the API. It is unknown
+// until a response has been received with a rate limiter configured.
+func (c *Client) Quota() Quota {
+	if c.Limiter == nil {
+		return Quota{}
+	}
+	return c.Limiter.Quota()
+}
+
+// BreakerState returns the state of the circuit breaker. It is always closed
+// when no breaker is configured.
+func (c *Client) BreakerState() BreakerState {
+	if c.Breaker == nil {
+		return BreakerClosed
+	}
+	return c.Breaker.State()
+}
+
+// FetchJoke fetches a joke from the API based on the given parameters and
+// returns its text. It is a convenience wrapper around GetJoke for callers
+// that only need the text.
 func (c *Client) FetchJoke(input string) (string, error) {
-	// Parse input to extract category and joke type
-	input = strings.ToLower(input)
+	return c.FetchJokeContext(context.Background(), input)
+}
+
+// FetchJokeContext is like FetchJoke but stops waiting when ctx is cancelled
+func (c *Client) FetchJokeContext(ctx context.Context, input string) (string, error) {
+	joke, err := c.GetJoke(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return joke.Text(), nil
+}
+
+// GetJoke fetches a joke for a free text request and returns it with its
+// metadata. It is a thin adapter that parses the input with ParseQuery and
+// hands the result to FetchJokeWithQuery.
+func (c *Client) GetJoke(ctx context.Context, input string) (*model.Joke, error) {
+	response, err := c.FetchJokeWithQuery(ctx, ParseQuery(input))
+	if err != nil {
+		return nil, err
+	}
+
+	joke := model.JokeFromResponse(*response)
+	return &joke, nil
+}
+
+// FetchJokeWithQuery fetches a single joke matching the structured query
+func (c *Client) FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	query = c.resolveLanguage(ctx, c.withDefaults(query)).Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if query.Amount > 1 {
+		return nil, fmt.Errorf("invalid query: use FetchJokes for an amount of %d", query.Amount)
+	}
+
+	for attempt := 1; ; attempt++ {
+		// Only the first attempt may be served from the cache; later ones
+		// replace a cached joke that was already shown
+		body, cached, err := c.fetchBody(ctx, query, attempt == 1)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the JSON response
+		var joke model.JokeResponse
+		err = json.Unmarshal(body, &joke)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
 
-	// Initialize parameters
-	jokeCategory := "Any" // Default category
-	jokeType := ""        // No specific type by default
-	blacklistFlags := []string{}
+		if err := checkJokeType(joke); err != nil {
+			return nil, err
+		}
+		joke.Provider = c.Name()
+
+		// Don't take the API's word for it: check the joke against the
+		// blacklist, safe mode and content policy
+		if err := c.Policy.Check(query, joke); err != nil {
+			if c.Debug {
+				c.writeDebug("REJECTED: %v\n", err)
+			}
+			// Refetching a joke asked for by ID would only get the same joke
+			if query.IDRange.single() {
+				return nil, err
+			}
+			if attempt >= c.Policy.attempts() {
+				return nil, fmt.Errorf("no acceptable joke after %d attempts: %w", attempt, err)
+			}
+			continue
+		}
+		if !cached {
+			c.cacheBody(query, body)
+		}
+
+		// A joke asked for by ID is wanted even if it was shown before
+		if !c.NoRepeats || query.IDRange.single() || !c.seen.markSeen(joke.ID) || attempt >= maxRefetches {
+			return &joke, nil
+		}
 
-	// Check for joke type
-	if strings.Contains(input, "twopart") {
-		jokeType = "twopart"
-	} else if strings.Contains(input, "single") {
-		jokeType = "single"
+		if c.Debug {
+			c.writeDebug("REPEAT: joke %d already shown, refetching\n", joke.ID)
+		}
 	}
+}
+
+// FetchJokeByID fetches the joke with the given ID
+func (c *Client) FetchJokeByID(ctx context.Context, id int) (*model.JokeResponse, error) {
+	return c.FetchJokeWithQuery(ctx, JokeQuery{IDRange: &IDRange{From: id, To: id}})
+}
 
-	// Check for categories
-	categories := []string{"programming", "misc", "dark", "pun", "spooky", "christmas"}
-	for _, category := range categories {
-		if strings.Contains(input, category) {
-			jokeCategory = strings.Title(category) // Capitalize first letter for API
-			break
+// FetchJokes fetches query.Amount jokes in a single request. JokeAPI only
+// wraps jokes in the batch envelope when more than one is requested, so
+// smaller amounts are served by FetchJokeWithQuery. In NoRepeats mode jokes
+// already shown are dropped, and a batch with nothing new is refetched.
+func (c *Client) FetchJokes(ctx context.Context, query JokeQuery) ([]model.JokeResponse, error) {
+	if query.Amount <= 1 {
+		joke, err := c.FetchJokeWithQuery(ctx, query)
+		if err != nil {
+			return nil, err
 		}
+		return []model.JokeResponse{*joke}, nil
+	}
+
+	query = c.resolveLanguage(ctx, c.withDefaults(query)).Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
 	}
 
-	// Check for blacklist flags
-	blacklistOptions := []string{"religious", "political", "racist", "sexist", "explicit"}
-	nsfw := false
+	for attempt := 1; ; attempt++ {
+		// A cached batch would show the same jokes again in NoRepeats mode
+		body, cached, err := c.fetchBody(ctx, query, attempt == 1 && !c.NoRepeats)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the batch envelope
+		var batch model.JokesResponse
+		err = json.Unmarshal(body, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		var jokes, repeats []model.JokeResponse
+		rejected := false
+		for _, joke := range batch.Jokes {
+			if err := checkJokeType(joke); err != nil {
+				return nil, err
+			}
+			joke.Provider = c.Name()
+
+			// Drop jokes that slipped past the API's filters
+			if err := c.Policy.Check(query, joke); err != nil {
+				if c.Debug {
+					c.writeDebug("REJECTED: %v\n", err)
+				}
+				rejected = true
+				continue
+			}
+
+			if c.NoRepeats && c.seen.markSeen(joke.ID) {
+				repeats = append(repeats, joke)
+				continue
+			}
+			jokes = append(jokes, joke)
+		}
+
+		if !cached && !rejected {
+			c.cacheBody(query, body)
+		}
+
+		switch {
+		case len(jokes) > 0:
+			return jokes, nil
+		case len(repeats) == 0:
+			return nil, fmt.Errorf("%w: every joke in the batch was rejected", ErrPolicyViolation)
+		case attempt >= maxRefetches:
+			return repeats, nil
+		}
 
-	// Check if NSFW is explicitly requested (expand patterns to check)
-	if strings.Contains(input, "nsfw") || strings.Contains(input, "dirty") ||
-		strings.Contains(input, "adult") || strings.Contains(input, "explicit") {
-		nsfw = true
+		if c.Debug {
+			c.writeDebug("REPEAT: every joke in the batch was already shown, refetching\n")
+		}
 	}
+}
+
+// ResetSeen forgets which jokes were already returned in NoRepeats mode
+func (c *Client) ResetSeen() {
+	c.seen.reset()
+}
 
-	// Add other blacklist flags, but not NSFW if it was requested
-	for _, flag := range blacklistOptions {
-		if strings.Contains(input, "no "+flag) || strings.Contains(input, "not "+flag) {
-			blacklistFlags = append(blacklistFlags, flag)
+// fetchBody returns the API response for a normalized query, consulting the
+// cache first when useCache is set. It reports whether the body came from the
+// cache; fresh bodies are only cached by cacheBody once they pass the checks.
+func (c *Client) fetchBody(ctx context.Context, query JokeQuery, useCache bool) ([]byte, bool, error) {
+	if useCache && c.Cache != nil {
+		key := query.cacheKey()
+		if body, ok := c.Cache.Get(key); ok {
+			if c.Debug {
+				c.writeDebug("CACHE HIT: %s\n", key)
+			}
+			return body, true, nil
 		}
 	}
 
-	// Only blacklist NSFW if not explicitly requested
-	if !nsfw && (strings.Contains(input, "no nsfw") || strings.Contains(input, "not nsfw") || strings.Contains(input, "clean joke")) {
-		blacklistFlags = append(blacklistFlags, "nsfw")
+	body, err := c.get(ctx, c.jokeURL(query))
+	if err != nil {
+		return nil, false, err
+	}
+	return body, false, nil
+}
+
+// cacheBody stores an accepted response for a normalized query
+func (c *Client) cacheBody(query JokeQuery, body []byte) {
+	if c.Cache != nil {
+		c.Cache.Set(query.cacheKey(), body, c.CacheTTL)
 	}
+}
 
+// withDefaults fills in the client's language when the query does not name
+// one, and adds the safe mode and blacklist the client's settings require
+func (c *Client) withDefaults(query JokeQuery) JokeQuery {
+	if query.Language == "" {
+		query.Language = c.Language
+	}
+	query.SafeMode = query.SafeMode || c.SafeMode
+
+	// Ask the API to honour the policy so fewer jokes need refetching
+	return c.Policy.apply(query)
+}
+
+// jokeURL builds the request URL for a normalized query
+func (c *Client) jokeURL(query JokeQuery) string {
 	// Construct URL with proper path parameters
-	url := fmt.Sprintf("%s/%s", c.BaseURL, jokeCategory)
+	url := fmt.Sprintf("%s/%s", c.BaseURL, query.path())
+	if params := query.encode(); params != "" {
+		url += "?" + params
+	}
+	return url
+}
+
+// checkJokeType rejects jokes whose type cannot be formatted
+func checkJokeType(joke model.JokeResponse) error {
+	if joke.Type != TypeSingle && joke.Type != TypeTwoPart {
+		return fmt.Errorf("unknown joke type: %s", joke.Type)
+	}
+	return nil
+}
+
+// isSafe reports whether a joke is suitable for safe mode: marked safe by the
+// API and carrying none of the blacklist flags
+func isSafe(joke model.JokeResponse) bool {
+	return joke.Safe && joke.Flags.Empty()
+}
 
-	// Add query parameters
-	params := []string{}
+// FormatJoke renders a joke as display text
+func FormatJoke(joke *model.JokeResponse) string {
+	return model.JokeFromResponse(*joke).Text()
+}
 
-	if jokeType != "" {
-		params = append(params, "type="+jokeType)
+// get performs a GET request against the API and returns the response body,
+// retrying failed attempts according to the client's retry policy and failing
+// fast while the circuit breaker is open
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	if c.Breaker == nil {
+		return c.getWithRetries(ctx, url)
 	}
 
-	if len(blacklistFlags) > 0 {
-		params = append(params, "blacklistFlags="+strings.Join(blacklistFlags, ","))
+	// Fail fast instead of waiting out the timeout while the API is down
+	if err := c.Breaker.Allow(); err != nil {
+		if c.Debug {
+			c.writeDebug("REQUEST SKIPPED: %s: %v\n", url, err)
+		}
+		return nil, fmt.Errorf("joke API unavailable: %w", err)
 	}
 
-	if len(params) > 0 {
-		url += "?" + strings.Join(params, "&")
+	body, err := c.getWithRetries(ctx, url)
+
+	switch {
+	case isOutage(err) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		// A caller's deadline that ran out waiting for the API counts too,
+		// or a hanging API would never open the breaker
+		c.Breaker.Record(err)
+		if c.Debug && c.Breaker.State() == BreakerOpen {
+			c.writeDebug("CIRCUIT BREAKER OPEN for %v: %v\n", c.Breaker.Cooldown, err)
+		}
+	case ctx.Err() != nil:
+		c.Breaker.Skip()
+	default:
+		c.Breaker.Record(nil)
 	}
 
+	return body, err
+}
+
+// getWithRetries performs the request, retrying failures allowed by the retry policy
+func (c *Client) getWithRetries(ctx context.Context, url string) ([]byte, error) {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		body, retryAfter, err := c.getOnce(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+
+		if attempt >= c.Retry.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		// Prefer the server's Retry-After over our own backoff
+		wait := c.Retry.backoff(attempt)
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+
+		if c.Retry.MaxElapsed > 0 && time.Since(start)+wait > c.Retry.MaxElapsed {
+			if c.Debug {
+				c.writeDebug("RETRY BUDGET EXHAUSTED after %d attempts: %v\n", attempt, err)
+			}
+			return nil, err
+		}
+
+		if c.Debug {
+			c.writeDebug("RETRY %d/%d in %v: %v\n", attempt+1, c.Retry.MaxAttempts, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("API request cancelled while waiting to retry: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// MaxRequestTime returns the longest a request may take under the retry
+// policy: every attempt timing out, with the longest backoff between them,
+// but no more than the retry budget plus the last attempt it lets start.
+// Waits for the rate limiter or a Retry-After header are not included.
+func (c *Client) MaxRequestTime() time.Duration {
+	attempts := max(c.Retry.MaxAttempts, 1)
+	total := time.Duration(attempts) * c.Timeout
+	for retry := 1; retry < attempts; retry++ {
+		total += c.Retry.longestBackoff(retry)
+	}
+
+	if c.Retry.MaxElapsed > 0 {
+		total = min(total, c.Retry.MaxElapsed+c.Timeout)
+	}
+	return total
+}
+
+// getOnce performs a single request attempt. Alongside the body or error it
+// returns any wait the server asked for with a Retry-After header.
+func (c *Client) getOnce(ctx context.Context, url string) ([]byte, time.Duration, error) {
 	// Debug output for request URL
 	if c.Debug {
 		c.writeDebug("REQUEST: %s\n", url)
 	}
+	return c.send(ctx, http.MethodGet, url, nil)
+}
+
+// send performs a single request with an optional JSON payload, like getOnce
+func (c *Client) send(ctx context.Context, method, url string, payload []byte) ([]byte, time.Duration, error) {
+	// Wait for room in the rate limit window
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+	return c.do(ctx, method, url, payload)
+}
 
+// do performs a request like send without waiting for the rate limiter,
+// though the quota in the response is still recorded
+func (c *Client) do(ctx context.Context, method, url string, payload []byte) ([]byte, time.Duration, error) {
 	// Create a context with a timeout
-	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	attemptCtx, cancel := context.WithTimeout(ctx, c.Timeout)
 	defer cancel()
 
 	// Create a new request with the context
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(attemptCtx, method, url, reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
 	}
 
 	// Make the HTTP request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return "", fmt.Errorf("API request timed out after %v seconds", c.Timeout.Seconds())
+		if ctx.Err() != nil {
+			return nil, 0, fmt.Errorf("API request failed: %w", err)
+		}
+		if attemptCtx.Err() == context.DeadlineExceeded {
+			return nil, 0, &transportError{fmt.Errorf("API request timed out after %v seconds", c.Timeout.Seconds())}
 		}
-		return "", fmt.Errorf("API request failed: %w", err)
+		return nil, 0, &transportError{fmt.Errorf("API request failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
-	// Check the response status code
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+	if c.Limiter != nil {
+		c.Limiter.Update(resp.Header)
 	}
+	retryAfter := parseRetryAfter(resp.Header, time.Now())
 
 	// Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, 0, &transportError{fmt.Errorf("failed to read response body: %w", err)}
 	}
 
 	// Debug output for response JSON
@@ -216,22 +569,44 @@ func (c *Client) FetchJoke(input string) (string, error) {
 		}
 	}
 
-	// Unmarshal the JSON response
-	var joke model.JokeResponse
-	err = json.Unmarshal(body, &joke)
-	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
+	// JokeAPI reports errors in the body, sometimes with a 200 status
+	if apiErr := decodeAPIError(resp.StatusCode, body); apiErr != nil {
+		if c.Debug {
+			c.writeDebug("API ERROR: %v\n", apiErr)
+		}
+		return nil, retryAfter, apiErr
+	}
+
+	// Check the response status code
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, retryAfter, &statusError{StatusCode: resp.StatusCode}
 	}
 
-	// Format the joke based on its type
-	var formattedJoke string
-	if joke.Type == "single" {
-		formattedJoke = joke.Joke
-	} else if joke.Type == "twopart" {
-		formattedJoke = fmt.Sprintf("%s\n\n%s", joke.Setup, joke.Delivery)
-	} else {
-		return "", fmt.Errorf("unknown joke type: %s", joke.Type)
+	return body, 0, nil
+}
+
+// decodeAPIError returns the error payload in body, or nil if the response
+// is not an error. A 429 without a payload is still reported as an APIError
+// so callers can match it against model.ErrRateLimited.
+func decodeAPIError(status int, body []byte) *model.APIError {
+	var probe struct {
+		Error bool `json:"error"`
+	}
+	if json.Unmarshal(body, &probe) == nil && probe.Error {
+		var apiErr model.APIError
+		if json.Unmarshal(body, &apiErr) == nil {
+			apiErr.StatusCode = status
+			return &apiErr
+		}
+	}
+
+	if status == http.StatusTooManyRequests {
+		return &model.APIError{
+			Code:       model.CodeRateLimited,
+			Message:    http.StatusText(status),
+			StatusCode: status,
+		}
 	}
 
-	return formattedJoke, nil
+	return nil
 }
@@ -1,6 +1,7 @@
 package jokeclient_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
 )
 
 var _ = Describe("Joke Client", func() {
@@ -133,6 +135,17 @@ var _ = Describe("Joke Client", func() {
 				Expect(joke).To(ContainSubstring("I don't know, but the flag is a big plus!"))
 			})
 
+			It("should return a twopart joke with its metadata", func() {
+				joke, err := client.GetJoke(context.Background(), "Tell me a twopart joke")
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(joke.IsTwoPart()).To(BeTrue())
+				Expect(joke.Setup()).To(Equal("What's the best thing about Switzerland?"))
+				Expect(joke.Punchline()).To(Equal("I don't know, but the flag is a big plus!"))
+				Expect(joke.Category).To(Equal(model.CategoryMisc))
+				Expect(joke.Provider).To(Equal("JokeAPI"))
+			})
+
 			It("should handle default joke requests", func() {
 				joke, err := client.FetchJoke("Tell me any joke")
 				
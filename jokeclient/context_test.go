@@ -0,0 +1,74 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("Cancellation", func() {
+	var (
+		client  *jokeclient.Client
+		server  *httptest.Server
+		release chan struct{}
+	)
+
+	BeforeEach(func() {
+		release = make(chan struct{})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		client = jokeclient.NewClient(
+			jokeclient.WithBaseURL(server.URL),
+			jokeclient.WithRetryPolicy(jokeclient.RetryPolicy{
+				MaxAttempts:    5,
+				InitialBackoff: time.Second,
+			}),
+		)
+	})
+
+	AfterEach(func() {
+		close(release)
+		server.Close()
+	})
+
+	It("should abandon an in-flight request when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := client.FetchJokeContext(ctx, "any joke")
+
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should stop waiting between retries when the context is cancelled", func() {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		client.BaseURL = failing.URL
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.FetchJokeWithQuery(ctx, jokeclient.JokeQuery{})
+
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
@@ -0,0 +1,93 @@
+package jokeclient
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// DadJokeProvider serves one-liners from an icanhazdadjoke-style API
+type DadJokeProvider struct {
+	httpProvider
+}
+
+// dadJoke is a joke as returned by icanhazdadjoke
+type dadJoke struct {
+	ID     string `json:"id"`
+	Joke   string `json:"joke"`
+	Status int    `json:"status"`
+}
+
+// dadJokeSearch is the response of the icanhazdadjoke search endpoint
+type dadJokeSearch struct {
+	Results    []dadJoke `json:"results"`
+	TotalJokes int       `json:"total_jokes"`
+}
+
+// NewDadJokeProvider creates a provider for https://icanhazdadjoke.com
+func NewDadJokeProvider() *DadJokeProvider {
+	return &DadJokeProvider{httpProvider{
+		BaseURL:    "https://icanhazdadjoke.com",
+		Timeout:    5 * time.Second,
+		HTTPClient: newHTTPClient(),
+		UserAgent:  DefaultUserAgent,
+	}}
+}
+
+// Name identifies the provider
+func (p *DadJokeProvider) Name() string {
+	return "icanhazdadjoke"
+}
+
+// FetchJokeWithQuery fetches a random dad joke, or one containing
+// query.Contains. Dad jokes are single-part English puns, so other
+// categories, types and languages are unsupported. icanhazdadjoke IDs are
+// strings, so returned jokes have no numeric ID and ID is left at zero.
+func (p *DadJokeProvider) FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	query = query.Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	switch {
+	case query.Type == TypeTwoPart:
+		return nil, fmt.Errorf("%w: %s only has single jokes", ErrUnsupportedQuery, p.Name())
+	case !wantsCategory(query, "Pun") && !wantsCategory(query, "Misc"):
+		return nil, fmt.Errorf("%w: %s only has puns", ErrUnsupportedQuery, p.Name())
+	case query.Language != "" && query.Language != "en":
+		return nil, fmt.Errorf("%w: %s only has English jokes", ErrUnsupportedQuery, p.Name())
+	case query.IDRange != nil || query.Amount > 1:
+		return nil, fmt.Errorf("%w: %s cannot select jokes by ID or amount", ErrUnsupportedQuery, p.Name())
+	}
+
+	var joke dadJoke
+	if query.Contains == "" {
+		if err := p.getJSON(ctx, p.BaseURL+"/", &joke); err != nil {
+			return nil, err
+		}
+	} else {
+		var search dadJokeSearch
+		searchURL := fmt.Sprintf("%s/search?limit=30&term=%s", p.BaseURL, url.QueryEscape(query.Contains))
+		if err := p.getJSON(ctx, searchURL, &search); err != nil {
+			return nil, err
+		}
+		if len(search.Results) == 0 {
+			return nil, fmt.Errorf("%w: %s has no jokes about %q", model.ErrNoMatch, p.Name(), query.Contains)
+		}
+		joke = search.Results[rand.Intn(len(search.Results))]
+	}
+
+	response := &model.JokeResponse{
+		Category: "Pun",
+		Type:     TypeSingle,
+		Joke:     joke.Joke,
+		Safe:     true,
+		Lang:     "en",
+		Provider: p.Name(),
+	}
+	return response, nil
+}
@@ -0,0 +1,68 @@
+package jokeclient_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("API error payloads", func() {
+	var (
+		client *jokeclient.Client
+		server *httptest.Server
+		status int
+		body   string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+
+		client = jokeclient.NewClient()
+		client.BaseURL = server.URL
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should decode an error payload sent with a 200 status", func() {
+		status = http.StatusOK
+		body = `{"error": true, "internalError": false, "code": 106, "message": "No matching joke found", "causedBy": ["No jokes were found that match your provided filter(s)."]}`
+
+		_, err := client.FetchJoke("Tell me a pun joke")
+
+		var apiErr *model.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.Code).To(Equal(106))
+		Expect(apiErr.StatusCode).To(Equal(http.StatusOK))
+		Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+	})
+
+	It("should decode an error payload sent with a 400 status", func() {
+		status = http.StatusBadRequest
+		body = `{"error": true, "code": 106, "message": "No matching joke found", "causedBy": ["The specified category is invalid"]}`
+
+		_, err := client.FetchJoke("Tell me a pun joke")
+
+		Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeTrue())
+	})
+
+	It("should report a bare 429 as rate limited", func() {
+		status = http.StatusTooManyRequests
+		body = ``
+
+		_, err := client.FetchJoke("Tell me a pun joke")
+
+		Expect(errors.Is(err, model.ErrRateLimited)).To(BeTrue())
+	})
+})
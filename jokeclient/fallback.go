@@ -0,0 +1,166 @@
+package jokeclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// Defaults for each stage of a FallbackProvider
+const (
+	DefaultProviderTimeout  = 8 * time.Second
+	DefaultBreakerThreshold = 3
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// FallbackStage is one provider in a fallback chain
+type FallbackStage struct {
+	Provider JokeProvider
+	Timeout  time.Duration   // Limit for this provider, zero for none
+	Breaker  *CircuitBreaker // Skips the provider while it is failing, nil for none
+}
+
+// ProviderFailure records why a provider in a fallback chain was skipped
+type ProviderFailure struct {
+	Provider string
+	Err      error
+}
+
+// FallbackError is returned when every provider in a chain failed
+type FallbackError struct {
+	Failures []ProviderFailure
+}
+
+// Error lists each provider's failure
+func (e *FallbackError) Error() string {
+	reasons := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		reasons[i] = fmt.Sprintf("%s: %v", failure.Provider, failure.Err)
+	}
+	return "all joke providers failed (" + strings.Join(reasons, "; ") + ")"
+}
+
+// Unwrap exposes the individual failures to errors.Is and errors.As
+func (e *FallbackError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// FallbackProvider tries a chain of providers in order and returns the first
+// joke served, so an outage of one source does not fail the request
+type FallbackProvider struct {
+	Stages []*FallbackStage
+	Log    func(format string, args ...interface{}) // Debug log for failures, nil to disable
+}
+
+// NewFallbackProvider chains providers in the given order, each with the
+// default timeout and its own circuit breaker
+func NewFallbackProvider(providers ...JokeProvider) *FallbackProvider {
+	stages := make([]*FallbackStage, len(providers))
+	for i, provider := range providers {
+		stages[i] = &FallbackStage{
+			Provider: provider,
+			Timeout:  DefaultProviderTimeout,
+			Breaker:  NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown),
+		}
+	}
+	return &FallbackProvider{Stages: stages}
+}
+
+// Name lists the providers in the chain
+func (f *FallbackProvider) Name() string {
+	names := make([]string, len(f.Stages))
+	for i, stage := range f.Stages {
+		names[i] = stage.Provider.Name()
+	}
+	return strings.Join(names, ", then ")
+}
+
+// FetchJokeWithQuery returns the first joke served by a provider in the chain.
+// The joke's Provider field names the provider that served it. A provider
+// whose jokes violate the content policy ends the chain with that error.
+func (f *FallbackProvider) FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	var failures []ProviderFailure
+
+	for _, stage := range f.Stages {
+		name := stage.Provider.Name()
+
+		joke, err := stage.fetch(ctx, query)
+		if err == nil {
+			if len(failures) > 0 {
+				f.log("FALLBACK: %s served the joke after %d provider(s) failed\n", name, len(failures))
+			}
+			return joke, nil
+		}
+
+		// The caller gave up, so there is no point trying anyone else
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		// The provider is up but its jokes were blocked, which is no outage
+		if errors.Is(err, ErrPolicyViolation) {
+			f.log("FALLBACK: %s jokes were blocked: %v\n", name, err)
+			return nil, err
+		}
+
+		f.log("FALLBACK: %s failed: %v\n", name, err)
+		failures = append(failures, ProviderFailure{Provider: name, Err: err})
+	}
+
+	if len(failures) == 0 {
+		return nil, errors.New("no joke providers configured")
+	}
+	return nil, &FallbackError{Failures: failures}
+}
+
+// fetch asks the stage's provider for a joke within its timeout and breaker
+func (s *FallbackStage) fetch(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	if s.Breaker != nil {
+		if err := s.Breaker.Allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	stageCtx := ctx
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		stageCtx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	joke, err := s.Provider.FetchJokeWithQuery(stageCtx, query)
+
+	// Running out of the stage's time is an outage; the caller cancelling is not
+	timedOut := err != nil && stageCtx.Err() != nil && ctx.Err() == nil
+	if timedOut {
+		err = fmt.Errorf("no response within %v: %w", s.Timeout, err)
+	}
+
+	if s.Breaker != nil {
+		switch {
+		case isOutage(err) || timedOut:
+			s.Breaker.Record(err)
+		case ctx.Err() != nil:
+			s.Breaker.Skip()
+		default:
+			s.Breaker.Record(nil)
+		}
+	}
+
+	return joke, err
+}
+
+// log writes to the debug log if one is configured
+func (f *FallbackProvider) log(format string, args ...interface{}) {
+	if f.Log != nil {
+		f.Log(format, args...)
+	}
+}
@@ -0,0 +1,227 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+// stubProvider returns a canned joke or error and counts its calls
+type stubProvider struct {
+	name  string
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (s *stubProvider) Name() string {
+	return s.name
+}
+
+func (s *stubProvider) FetchJokeWithQuery(ctx context.Context, query jokeclient.JokeQuery) (*model.JokeResponse, error) {
+	s.calls++
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.JokeResponse{Type: "single", Joke: "From " + s.name, Provider: s.name}, nil
+}
+
+var _ = Describe("FallbackProvider", func() {
+	var (
+		primary, secondary, local *stubProvider
+		chain                     *jokeclient.FallbackProvider
+		logged                    []string
+	)
+
+	BeforeEach(func() {
+		primary = &stubProvider{name: "Primary"}
+		secondary = &stubProvider{name: "Secondary"}
+		local = &stubProvider{name: "Local"}
+		chain = jokeclient.NewFallbackProvider(primary, secondary, local)
+
+		logged = nil
+		chain.Log = func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}
+	})
+
+	It("should be named after its providers in order", func() {
+		Expect(chain.Name()).To(Equal("Primary, then Secondary, then Local"))
+	})
+
+	It("should use the first provider when it succeeds", func() {
+		joke, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Provider).To(Equal("Primary"))
+		Expect(secondary.calls).To(BeZero())
+		Expect(logged).To(BeEmpty())
+	})
+
+	It("should fall through to the next provider and log why", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		jokeAPI := jokeclient.NewClient(jokeclient.WithBaseURL(server.URL))
+		chain = jokeclient.NewFallbackProvider(jokeAPI, secondary)
+		chain.Log = func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}
+
+		joke, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Provider).To(Equal("Secondary"))
+		Expect(logged).To(ContainElement(ContainSubstring("JokeAPI failed: API request failed with status code: 503")))
+	})
+
+	It("should skip providers that cannot serve the query", func() {
+		primary.err = fmt.Errorf("two-part jokes: %w", jokeclient.ErrUnsupportedQuery)
+		secondary.err = model.ErrNoMatch
+
+		joke, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Provider).To(Equal("Local"))
+	})
+
+	It("should stop at a provider whose jokes were blocked", func() {
+		primary.err = &jokeclient.PolicyViolation{JokeID: 7, Reason: "not safe for safe mode"}
+
+		_, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+		Expect(secondary.calls).To(BeZero())
+		Expect(chain.Stages[0].Breaker.State()).To(Equal(jokeclient.BreakerClosed))
+	})
+
+	It("should report every failure when all providers fail", func() {
+		primary.err = errors.New("connection refused")
+		secondary.err = jokeclient.ErrUnsupportedQuery
+		local.err = model.ErrNoMatch
+
+		_, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		var fallbackErr *jokeclient.FallbackError
+		Expect(errors.As(err, &fallbackErr)).To(BeTrue())
+		Expect(fallbackErr.Failures).To(HaveLen(3))
+		Expect(err).To(MatchError(ContainSubstring("Primary: connection refused")))
+		Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+	})
+
+	It("should give up on a provider that exceeds its timeout", func() {
+		primary.delay = time.Second
+		chain.Stages[0].Timeout = 20 * time.Millisecond
+
+		joke, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Provider).To(Equal("Secondary"))
+		Expect(logged).To(ContainElement(ContainSubstring("Primary failed: no response within 20ms")))
+	})
+
+	It("should stop calling a provider once its circuit breaker opens", func() {
+		primary.delay = time.Second
+		chain.Stages[0].Timeout = 10 * time.Millisecond
+		chain.Stages[0].Breaker = jokeclient.NewCircuitBreaker(2, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			_, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(primary.calls).To(Equal(2))
+		Expect(chain.Stages[0].Breaker.State()).To(Equal(jokeclient.BreakerOpen))
+		Expect(logged).To(ContainElement(ContainSubstring("circuit breaker open")))
+	})
+
+	It("should not open the breaker for queries a provider cannot serve", func() {
+		primary.err = model.ErrNoMatch
+		chain.Stages[0].Breaker = jokeclient.NewCircuitBreaker(1, time.Minute)
+
+		chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(chain.Stages[0].Breaker.State()).To(Equal(jokeclient.BreakerClosed))
+	})
+
+	It("should stop when the caller cancels", func() {
+		primary.delay = time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := chain.FetchJokeWithQuery(ctx, jokeclient.JokeQuery{})
+
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(secondary.calls).To(BeZero())
+	})
+
+	Describe("with a JokeAPI client that hangs", func() {
+		var (
+			server   *httptest.Server
+			client   *jokeclient.Client
+			requests atomic.Int32
+		)
+
+		BeforeEach(func() {
+			requests.Store(0)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				<-r.Context().Done()
+			}))
+			client = jokeclient.NewClient(
+				jokeclient.WithBaseURL(server.URL),
+				jokeclient.WithCircuitBreaker(jokeclient.NewCircuitBreaker(3, time.Minute)),
+			)
+
+			// Wired like the app: the stage relies on the client's breaker
+			chain = jokeclient.NewFallbackProvider(client, local)
+			chain.Stages[0].Timeout = 50 * time.Millisecond
+			chain.Stages[0].Breaker = nil
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should open the client's breaker when the stage times out", func() {
+			for i := 0; i < 4; i++ {
+				joke, err := chain.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(joke.Provider).To(Equal("Local"))
+			}
+
+			Expect(client.BreakerState()).To(Equal(jokeclient.BreakerOpen))
+			Expect(requests.Load()).To(BeEquivalentTo(3))
+		})
+
+		It("should not count requests the caller cancelled", func() {
+			for i := 0; i < 4; i++ {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+
+				_, err := chain.FetchJokeWithQuery(ctx, jokeclient.JokeQuery{})
+				Expect(err).To(MatchError(context.Canceled))
+			}
+
+			Expect(client.BreakerState()).To(Equal(jokeclient.BreakerClosed))
+		})
+	})
+})
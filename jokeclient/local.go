@@ -0,0 +1,259 @@
+package jokeclient
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+//go:embed corpus/jokes.json
+var defaultCorpus []byte
+
+// LocalProvider serves jokes from a corpus loaded into memory, so jokes are
+// available without a network connection
+type LocalProvider struct {
+	jokes []model.JokeResponse
+}
+
+// NewLocalProvider creates a provider serving the given jokes
+func NewLocalProvider(jokes []model.JokeResponse) *LocalProvider {
+	return &LocalProvider{jokes: jokes}
+}
+
+// DefaultLocalProvider creates a provider serving the small corpus built into the binary
+func DefaultLocalProvider() *LocalProvider {
+	jokes, err := parseJSONCorpus(defaultCorpus)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in joke corpus: %v", err))
+	}
+	return NewLocalProvider(jokes)
+}
+
+// LoadLocalProvider creates a provider from a corpus file. The format is
+// chosen by extension: .json, .yaml/.yml or .csv.
+func LoadLocalProvider(path string) (*LocalProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read joke corpus: %w", err)
+	}
+
+	var jokes []model.JokeResponse
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		jokes, err = parseJSONCorpus(data)
+	case ".yaml", ".yml":
+		jokes, err = parseYAMLCorpus(data)
+	case ".csv":
+		jokes, err = parseCSVCorpus(strings.NewReader(string(data)))
+	default:
+		return nil, fmt.Errorf("unsupported joke corpus format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse joke corpus %s: %w", path, err)
+	}
+
+	for i, joke := range jokes {
+		if err := checkJokeType(joke); err != nil {
+			return nil, fmt.Errorf("joke %d in %s: %w", i+1, path, err)
+		}
+	}
+
+	return NewLocalProvider(jokes), nil
+}
+
+// Name identifies the provider
+func (p *LocalProvider) Name() string {
+	return "Local corpus"
+}
+
+// Len returns the number of jokes in the corpus
+func (p *LocalProvider) Len() int {
+	return len(p.jokes)
+}
+
+// FetchJokeWithQuery picks a random joke from the corpus that satisfies the
+// query's categories, type, blacklist flags, language, search string, ID range
+// and safe mode
+func (p *LocalProvider) FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	query = query.Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	var candidates []model.JokeResponse
+	for _, joke := range p.jokes {
+		if matchesQuery(query, joke) {
+			candidates = append(candidates, joke)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w in the local corpus", model.ErrNoMatch)
+	}
+
+	joke := candidates[rand.Intn(len(candidates))]
+	joke.Provider = p.Name()
+	return &joke, nil
+}
+
+// matchesQuery reports whether joke satisfies every filter in the query
+func matchesQuery(query JokeQuery, joke model.JokeResponse) bool {
+	if !wantsCategory(query, joke.Category) {
+		return false
+	}
+
+	if query.Type != "" && query.Type != joke.Type {
+		return false
+	}
+
+	for _, flag := range query.BlacklistFlags {
+		if hasFlag(joke, flag) {
+			return false
+		}
+	}
+
+	// Jokes without a language are taken to be English, like JokeAPI's default
+	language, jokeLanguage := query.Language, joke.Lang
+	if language == "" {
+		language = "en"
+	}
+	if jokeLanguage == "" {
+		jokeLanguage = "en"
+	}
+	if language != jokeLanguage {
+		return false
+	}
+
+	if query.Contains != "" {
+		text := strings.ToLower(joke.Joke + " " + joke.Setup + " " + joke.Delivery)
+		if !strings.Contains(text, strings.ToLower(query.Contains)) {
+			return false
+		}
+	}
+
+	if query.IDRange != nil && (joke.ID < query.IDRange.From || joke.ID > query.IDRange.To) {
+		return false
+	}
+
+	if query.SafeMode && !isSafe(joke) {
+		return false
+	}
+
+	return true
+}
+
+// hasFlag reports whether joke is marked with the named flag
+func hasFlag(joke model.JokeResponse, flag string) bool {
+	return joke.Flags.Has(flag)
+}
+
+// parseJSONCorpus accepts a list of jokes or JokeAPI's batch envelope
+func parseJSONCorpus(data []byte) ([]model.JokeResponse, error) {
+	var jokes []model.JokeResponse
+	if err := json.Unmarshal(data, &jokes); err == nil {
+		return jokes, nil
+	}
+
+	var batch model.JokesResponse
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+	return batch.Jokes, nil
+}
+
+// parseYAMLCorpus accepts the same shapes as parseJSONCorpus. The document is
+// converted to JSON so the field names match model.JokeResponse's JSON tags.
+func parseYAMLCorpus(data []byte) ([]model.JokeResponse, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONCorpus(converted)
+}
+
+// parseCSVCorpus reads jokes from CSV with a header row naming the columns.
+// Recognised columns are id, category, type, joke, setup, delivery, flags,
+// safe and lang; flags are separated by semicolons.
+func parseCSVCorpus(r io.Reader) ([]model.JokeResponse, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var jokes []model.JokeResponse
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		joke := model.JokeResponse{
+			Category: field(record, "category"),
+			Type:     field(record, "type"),
+			Joke:     field(record, "joke"),
+			Setup:    field(record, "setup"),
+			Delivery: field(record, "delivery"),
+			Lang:     field(record, "lang"),
+		}
+
+		if id := field(record, "id"); id != "" {
+			if joke.ID, err = strconv.Atoi(id); err != nil {
+				return nil, fmt.Errorf("line %d: invalid id %q", line, id)
+			}
+		}
+
+		if safe := field(record, "safe"); safe != "" {
+			if joke.Safe, err = strconv.ParseBool(safe); err != nil {
+				return nil, fmt.Errorf("line %d: invalid safe value %q", line, safe)
+			}
+		}
+
+		for _, flag := range strings.Split(field(record, "flags"), ";") {
+			if flag = strings.ToLower(strings.TrimSpace(flag)); flag == "" {
+				continue
+			}
+			if err := joke.Flags.Set(flag); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+
+		jokes = append(jokes, joke)
+	}
+
+	return jokes, nil
+}
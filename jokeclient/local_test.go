@@ -0,0 +1,154 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("LocalProvider", func() {
+	writeCorpus := func(name, content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	Describe("loading a corpus", func() {
+		It("should load a JSON corpus", func() {
+			provider, err := jokeclient.LoadLocalProvider(writeCorpus("jokes.json", `[
+				{"category": "Pun", "type": "single", "joke": "A JSON pun", "flags": {}, "id": 1, "lang": "en"}
+			]`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(provider.Len()).To(Equal(1))
+		})
+
+		It("should load a JSON corpus in JokeAPI's batch envelope", func() {
+			provider, err := jokeclient.LoadLocalProvider(writeCorpus("jokes.json", `{"amount": 2, "jokes": [
+				{"category": "Pun", "type": "single", "joke": "One", "flags": {}, "id": 1},
+				{"category": "Pun", "type": "single", "joke": "Two", "flags": {}, "id": 2}
+			]}`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(provider.Len()).To(Equal(2))
+		})
+
+		It("should load a YAML corpus", func() {
+			provider, err := jokeclient.LoadLocalProvider(writeCorpus("jokes.yaml", `
+- category: Programming
+  type: twopart
+  setup: Why do Java developers wear glasses?
+  delivery: Because they don't C#.
+  flags:
+    nsfw: false
+  id: 3
+  lang: en
+`))
+
+			Expect(err).NotTo(HaveOccurred())
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Setup).To(Equal("Why do Java developers wear glasses?"))
+			Expect(joke.ID).To(Equal(3))
+		})
+
+		It("should load a CSV corpus with semicolon separated flags", func() {
+			provider, err := jokeclient.LoadLocalProvider(writeCorpus("jokes.csv",
+				"id,category,type,joke,setup,delivery,flags,safe,lang\n"+
+					"1,Misc,single,A clean one,,,,true,en\n"+
+					"2,Dark,single,A rude one,,,nsfw;explicit,false,en\n"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(provider.Len()).To(Equal(2))
+
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Categories: []string{"Dark"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Flags.Nsfw).To(BeTrue())
+			Expect(joke.Flags.Explicit).To(BeTrue())
+			Expect(joke.Safe).To(BeFalse())
+		})
+
+		It("should reject unknown formats and invalid jokes", func() {
+			_, err := jokeclient.LoadLocalProvider(writeCorpus("jokes.txt", "knock knock"))
+			Expect(err).To(MatchError(ContainSubstring("unsupported joke corpus format")))
+
+			_, err = jokeclient.LoadLocalProvider(writeCorpus("jokes.json", `[{"type": "limerick"}]`))
+			Expect(err).To(MatchError(ContainSubstring("unknown joke type")))
+		})
+	})
+
+	Describe("filtering", func() {
+		var provider *jokeclient.LocalProvider
+
+		BeforeEach(func() {
+			provider = jokeclient.DefaultLocalProvider()
+		})
+
+		It("should serve jokes from the built-in corpus", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Provider).To(Equal("Local corpus"))
+			Expect(joke.Lang).To(Equal("en"))
+		})
+
+		It("should filter by category and type", func() {
+			for i := 0; i < 10; i++ {
+				joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{
+					Categories: []string{"programming"},
+					Type:       jokeclient.TypeTwoPart,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(joke.Category).To(Equal("Programming"))
+				Expect(joke.Type).To(Equal("twopart"))
+			}
+		})
+
+		It("should filter by language, search string and ID range", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Language: "de"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Lang).To(Equal("de"))
+
+			joke, err = provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Contains: "COFFEE"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Joke).To(ContainSubstring("coffee"))
+
+			joke, err = provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{IDRange: &jokeclient.IDRange{From: 7, To: 7}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.ID).To(Equal(7))
+		})
+
+		It("should exclude jokes with blacklisted flags", func() {
+			provider, err := jokeclient.LoadLocalProvider(writeCorpus("jokes.csv",
+				"id,category,type,joke,flags\n"+
+					"1,Misc,single,Political,political\n"+
+					"2,Misc,single,Harmless,\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 10; i++ {
+				joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{BlacklistFlags: []string{"political"}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(joke.Joke).To(Equal("Harmless"))
+			}
+
+			_, err = provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{
+				IDRange:        &jokeclient.IDRange{From: 1, To: 1},
+				BlacklistFlags: []string{"political"},
+			})
+			Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		})
+
+		It("should report no match when nothing fits", func() {
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Contains: "xylophone"})
+
+			Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		})
+	})
+})
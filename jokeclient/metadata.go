@@ -0,0 +1,172 @@
+package jokeclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// DefaultMetadataTTL is how long the categories, flags and other API
+// metadata stay cached. They change far less often than jokes.
+const DefaultMetadataTTL = 24 * time.Hour
+
+// Categories returns the joke categories JokeAPI currently serves, without
+// the "Any" pseudo-category
+func (c *Client) Categories(ctx context.Context) ([]string, error) {
+	var resp model.CategoriesResponse
+	if err := c.getMetadata(ctx, "/categories", &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	categories := []string{}
+	for _, category := range resp.Categories {
+		if !strings.EqualFold(category, "any") {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+// Flags returns the blacklist flags JokeAPI currently supports
+func (c *Client) Flags(ctx context.Context) ([]string, error) {
+	var resp model.FlagsResponse
+	if err := c.getMetadata(ctx, "/flags", &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch flags: %w", err)
+	}
+	return resp.Flags, nil
+}
+
+// LangCode returns the code JokeAPI uses for a language name such as "german"
+func (c *Client) LangCode(ctx context.Context, language string) (string, error) {
+	var resp model.LangCodeResponse
+	path := "/langcode/" + url.PathEscape(strings.ToLower(strings.TrimSpace(language)))
+	if err := c.getMetadata(ctx, path, &resp); err != nil {
+		return "", fmt.Errorf("failed to look up language %q: %w", language, err)
+	}
+	if resp.Code == "" {
+		return "", fmt.Errorf("no language code for %q", language)
+	}
+	return resp.Code, nil
+}
+
+// Info returns JokeAPI's description of itself and the jokes it serves
+func (c *Client) Info(ctx context.Context) (*model.InfoResponse, error) {
+	var resp model.InfoResponse
+	if err := c.getMetadata(ctx, "/info", &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch API info: %w", err)
+	}
+	return &resp, nil
+}
+
+// Discover fetches the categories, flags and languages JokeAPI currently
+// supports and makes them the vocabulary for parsing and validating queries,
+// so categories added upstream can be asked for without a code change. Flags
+// model.Flags has no field for are left out, and lists that cannot be
+// fetched keep their current values.
+func (c *Client) Discover(ctx context.Context) (Vocabulary, error) {
+	var discovered Vocabulary
+	var errs []error
+
+	categories, err := c.Categories(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	discovered.Categories = categories
+
+	flags, err := c.Flags(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, flag := range flags {
+		// A flag jokes cannot carry could be blacklisted but never checked
+		if !slices.Contains(model.FlagNames, strings.ToLower(flag)) {
+			c.writeDebug("DISCOVERY: ignoring flag %s, which jokes cannot carry\n", flag)
+			continue
+		}
+		discovered.Flags = append(discovered.Flags, flag)
+	}
+
+	info, err := c.Info(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for language := range info.Jokes.IDRange {
+			discovered.Languages = append(discovered.Languages, language)
+		}
+		slices.Sort(discovered.Languages)
+	}
+
+	SetVocabulary(discovered)
+	if len(errs) > 0 {
+		return discovered, fmt.Errorf("discovery incomplete: %w", errs[0])
+	}
+	return discovered, nil
+}
+
+// resolveLanguage replaces a language name such as "german" in the query
+// with its code, asking JokeAPI about names the parser does not know. Codes
+// and names that cannot be resolved are left for Validate to report.
+func (c *Client) resolveLanguage(ctx context.Context, query JokeQuery) JokeQuery {
+	language := strings.ToLower(strings.TrimSpace(query.Language))
+	if len(language) <= 2 {
+		return query
+	}
+
+	if code, ok := languageNames[language]; ok {
+		query.Language = code
+	} else if code, err := c.LangCode(ctx, language); err == nil {
+		query.Language = code
+	}
+	return query
+}
+
+// apiURL returns the URL of an endpoint beside /joke, such as /categories
+func (c *Client) apiURL(path string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(c.BaseURL, "/"), "/joke") + path
+}
+
+// getMetadata fetches a metadata endpoint into v, keeping the response in
+// the client's cache, or a cache of its own if it has none
+func (c *Client) getMetadata(ctx context.Context, path string, v interface{}) error {
+	cache := c.metadataCache()
+	key := "meta:" + path
+
+	body, ok := cache.Get(key)
+	if ok {
+		if c.Debug {
+			c.writeDebug("CACHE HIT: %s\n", key)
+		}
+	} else {
+		var err error
+		body, err = c.get(ctx, c.apiURL(path))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	if !ok {
+		cache.Set(key, body, DefaultMetadataTTL)
+	}
+	return nil
+}
+
+// metadataCache returns the cache metadata is kept in
+func (c *Client) metadataCache() Cache {
+	if c.Cache != nil {
+		return c.Cache
+	}
+	c.metaOnce.Do(func() {
+		c.metaCache = NewMemoryCache(32)
+	})
+	return c.metaCache
+}
@@ -0,0 +1,176 @@
+package jokeclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("API metadata", func() {
+	var (
+		server   *httptest.Server
+		client   *jokeclient.Client
+		requests map[string]int
+		lastJoke string
+	)
+
+	BeforeEach(func() {
+		requests = map[string]int{}
+		lastJoke = ""
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/categories", func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			fmt.Fprint(w, `{"error":false,"categories":["Any","Misc","Programming","Dark","Pun","Spooky","Christmas","Knock-Knock"],"categoryAliases":[{"alias":"Coding","resolved":"Programming"}]}`)
+		})
+		mux.HandleFunc("/flags", func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			fmt.Fprint(w, `{"error":false,"flags":["nsfw","religious","political","racist","sexist","explicit","gross"]}`)
+		})
+		mux.HandleFunc("/langcode/", func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			switch strings.TrimPrefix(r.URL.Path, "/langcode/") {
+			case "italian":
+				fmt.Fprint(w, `{"error":false,"code":"it"}`)
+			case "german":
+				fmt.Fprint(w, `{"error":false,"code":"de"}`)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":true,"code":106,"message":"No matching language found"}`)
+			}
+		})
+		mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			fmt.Fprint(w, `{"error":false,"version":"2.3.3","jokes":{"totalCount":1368,"types":["single","twopart"],"idRange":{"en":[0,318],"de":[0,35],"it":[0,12]},"safeJokes":[{"lang":"en","count":200}]},"jokeLanguages":3}`)
+		})
+		mux.HandleFunc("/joke/", func(w http.ResponseWriter, r *http.Request) {
+			lastJoke = r.URL.RequestURI()
+			fmt.Fprint(w, `{"error":false,"category":"Knock-Knock","type":"single","joke":"Knock knock.","id":1,"safe":true,"lang":"en"}`)
+		})
+
+		server = httptest.NewServer(mux)
+		client = jokeclient.NewClient()
+		client.BaseURL = server.URL + "/joke"
+
+		DeferCleanup(jokeclient.SetVocabulary, jokeclient.DefaultVocabulary())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should list the categories without Any", func() {
+		categories, err := client.Categories(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(categories).To(Equal([]string{"Misc", "Programming", "Dark", "Pun", "Spooky", "Christmas", "Knock-Knock"}))
+	})
+
+	It("should list the flags", func() {
+		flags, err := client.Flags(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(flags).To(ContainElement("gross"))
+	})
+
+	It("should look up language codes", func() {
+		code, err := client.LangCode(context.Background(), "Italian")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal("it"))
+
+		_, err = client.LangCode(context.Background(), "klingon")
+		Expect(err).To(MatchError(ContainSubstring(`failed to look up language "klingon"`)))
+	})
+
+	It("should describe the API", func() {
+		info, err := client.Info(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Version).To(Equal("2.3.3"))
+		Expect(info.Jokes.TotalCount).To(Equal(1368))
+		Expect(info.Jokes.IDRange["de"]).To(Equal([2]int{0, 35}))
+	})
+
+	It("should cache the results", func() {
+		for i := 0; i < 3; i++ {
+			_, err := client.Categories(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.Info(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(requests["/categories"]).To(Equal(1))
+		Expect(requests["/info"]).To(Equal(1))
+	})
+
+	It("should keep the results in the client's cache when it has one", func() {
+		cache := jokeclient.NewMemoryCache(10)
+		client.Cache = cache
+
+		_, err := client.Flags(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, ok := cache.Get("meta:/flags")
+		Expect(ok).To(BeTrue())
+	})
+
+	Describe("Discover", func() {
+		It("should make the discovered lists the vocabulary", func() {
+			vocabulary, err := client.Discover(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vocabulary.Languages).To(Equal([]string{"de", "en", "it"}))
+
+			Expect(jokeclient.KnownCategories()).To(ContainElement("Knock-Knock"))
+			Expect(jokeclient.KnownFlags()).To(Equal(jokeclient.DefaultVocabulary().Flags))
+			Expect(jokeclient.SupportedLanguages()).To(ContainElement("it"))
+		})
+
+		It("should let new categories be parsed, validated and fetched", func() {
+			_, err := client.Discover(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+
+			query := jokeclient.ParseQuery("tell me a knock-knock joke in italian")
+			Expect(query.Categories).To(Equal([]string{"Knock-Knock"}))
+			Expect(query.Validate()).To(Succeed())
+
+			category, err := jokeclient.ParseCategory("KNOCK-KNOCK")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(category).To(BeEquivalentTo("Knock-Knock"))
+
+			joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Categories: []string{"knock-knock"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Category).To(Equal("Knock-Knock"))
+			Expect(lastJoke).To(Equal("/joke/Knock-Knock"))
+		})
+
+		It("should leave out flags jokes cannot carry", func() {
+			vocabulary, err := client.Discover(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vocabulary.Flags).NotTo(ContainElement("gross"))
+
+			err = jokeclient.JokeQuery{BlacklistFlags: []string{"gross"}}.Validate()
+			Expect(err).To(MatchError("invalid blacklist flag: gross"))
+		})
+
+		It("should keep the built-in lists when the API cannot be reached", func() {
+			server.Close()
+
+			_, err := client.Discover(context.Background())
+			Expect(err).To(MatchError(ContainSubstring("discovery incomplete")))
+			Expect(jokeclient.KnownCategories()).To(Equal(jokeclient.DefaultVocabulary().Categories))
+		})
+	})
+
+	It("should resolve language names the parser does not know", func() {
+		_, err := client.Discover(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Language: "Italian"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lastJoke).To(Equal("/joke/Any?lang=it"))
+		Expect(requests["/langcode/italian"]).To(Equal(1))
+	})
+})
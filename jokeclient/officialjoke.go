@@ -0,0 +1,107 @@
+package jokeclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// OfficialJokeProvider serves two-part jokes from an official-joke-api-style API
+type OfficialJokeProvider struct {
+	httpProvider
+}
+
+// officialJoke is a joke as returned by the official joke API
+type officialJoke struct {
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Setup     string `json:"setup"`
+	Punchline string `json:"punchline"`
+}
+
+// officialJokeCategories maps the official joke API's types to JokeAPI categories
+var officialJokeCategories = map[string]string{
+	"general":     "Misc",
+	"knock-knock": "Misc",
+	"programming": "Programming",
+	"dad":         "Pun",
+}
+
+// NewOfficialJokeProvider creates a provider for https://official-joke-api.appspot.com
+func NewOfficialJokeProvider() *OfficialJokeProvider {
+	return &OfficialJokeProvider{httpProvider{
+		BaseURL:    "https://official-joke-api.appspot.com",
+		Timeout:    5 * time.Second,
+		HTTPClient: newHTTPClient(),
+		UserAgent:  DefaultUserAgent,
+	}}
+}
+
+// Name identifies the provider
+func (p *OfficialJokeProvider) Name() string {
+	return "Official Joke API"
+}
+
+// FetchJokeWithQuery fetches a random two-part joke. Queries that want
+// programming jokes but not Misc ones use the programming endpoint; others
+// are served from the general pool, and a joke from outside the requested
+// categories is reported as no match.
+func (p *OfficialJokeProvider) FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	query = query.Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	switch {
+	case query.Type == TypeSingle:
+		return nil, fmt.Errorf("%w: %s only has two-part jokes", ErrUnsupportedQuery, p.Name())
+	case !wantsCategory(query, "Programming") && !wantsCategory(query, "Misc") && !wantsCategory(query, "Pun"):
+		return nil, fmt.Errorf("%w: %s has no %s jokes", ErrUnsupportedQuery, p.Name(), query.path())
+	case query.Language != "" && query.Language != "en":
+		return nil, fmt.Errorf("%w: %s only has English jokes", ErrUnsupportedQuery, p.Name())
+	case query.Contains != "" || query.IDRange != nil || query.Amount > 1:
+		return nil, fmt.Errorf("%w: %s cannot search or select jokes", ErrUnsupportedQuery, p.Name())
+	}
+
+	var joke officialJoke
+	if len(query.Categories) > 0 && wantsCategory(query, "Programming") && !wantsCategory(query, "Misc") {
+		// The typed endpoint returns a list holding one joke
+		var jokes []officialJoke
+		if err := p.getJSON(ctx, p.BaseURL+"/jokes/programming/random", &jokes); err != nil {
+			return nil, err
+		}
+		if len(jokes) == 0 {
+			return nil, fmt.Errorf("%w: %s returned no jokes", model.ErrNoMatch, p.Name())
+		}
+		joke = jokes[0]
+	} else {
+		if err := p.getJSON(ctx, p.BaseURL+"/random_joke", &joke); err != nil {
+			return nil, err
+		}
+	}
+
+	category, ok := officialJokeCategories[strings.ToLower(joke.Type)]
+	if !ok {
+		category = "Misc"
+	}
+
+	// The general pool mixes categories, so it may miss the requested ones
+	if !wantsCategory(query, category) {
+		return nil, fmt.Errorf("%w: %s served a %s joke, which was not requested", model.ErrNoMatch, p.Name(), category)
+	}
+
+	response := &model.JokeResponse{
+		Category: category,
+		Type:     TypeTwoPart,
+		Setup:    joke.Setup,
+		Delivery: joke.Punchline,
+		ID:       joke.ID,
+		Safe:     true,
+		Lang:     "en",
+		Provider: p.Name(),
+	}
+	return response, nil
+}
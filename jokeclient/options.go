@@ -0,0 +1,134 @@
+package jokeclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultUserAgent is sent with every request unless overridden
+const DefaultUserAgent = "ai-agent-jokeclient (+https://github.com/AriT93/ai-agent)"
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithDebug enables writing requests and responses to the debug log
+func WithDebug(debug bool) Option {
+	return func(c *Client) {
+		c.Debug = debug
+	}
+}
+
+// WithDebugFile sets the file debug output is written to
+func WithDebugFile(path string) Option {
+	return func(c *Client) {
+		c.DebugFile = path
+	}
+}
+
+// WithBaseURL points the client at a different JokeAPI instance
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithTimeout sets the timeout for each request attempt
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithHTTPClient replaces the pooled HTTP client used for requests, e.g. to
+// configure proxies or custom TLS roots
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport sets the round tripper of the client's HTTP client
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		httpClient := http.Client{}
+		if c.HTTPClient != nil {
+			httpClient = *c.HTTPClient
+		}
+		httpClient.Transport = transport
+		c.HTTPClient = &httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.Retry = policy
+	}
+}
+
+// WithRateLimiter paces requests using the given rate limiter
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.Limiter = limiter
+	}
+}
+
+// WithLanguage sets the language used for queries that do not name one
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.Language = language
+	}
+}
+
+// WithSafeMode asks for and only accepts jokes suitable for everyone
+func WithSafeMode(safeMode bool) Option {
+	return func(c *Client) {
+		c.SafeMode = safeMode
+	}
+}
+
+// WithPolicy rejects and refetches jokes that violate policy
+func WithPolicy(policy *Policy) Option {
+	return func(c *Client) {
+		c.Policy = policy
+	}
+}
+
+// WithCircuitBreaker fails requests fast while breaker is open
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *Client) {
+		c.Breaker = breaker
+	}
+}
+
+// WithCache caches responses in cache for ttl, or DefaultCacheTTL if ttl is zero
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.Cache = cache
+		if ttl > 0 {
+			c.CacheTTL = ttl
+		}
+	}
+}
+
+// WithNoRepeats refetches jokes that were already returned in this session
+func WithNoRepeats(noRepeats bool) Option {
+	return func(c *Client) {
+		c.NoRepeats = noRepeats
+	}
+}
+
+// newHTTPClient creates the pooled HTTP client shared by all requests
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 10
+
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,92 @@
+package jokeclient_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("Client options", func() {
+	const jokeJSON = `{"error": false, "category": "Misc", "type": "single", "joke": "Served by a round tripper", "flags": {}, "id": 1, "safe": true, "lang": "en"}`
+
+	It("should apply options over the defaults", func() {
+		client := jokeclient.NewClient(
+			jokeclient.WithBaseURL("http://jokes.internal/joke"),
+			jokeclient.WithTimeout(2*time.Second),
+			jokeclient.WithRetryPolicy(jokeclient.DefaultRetryPolicy()),
+			jokeclient.WithLanguage("de"),
+		)
+
+		Expect(client.BaseURL).To(Equal("http://jokes.internal/joke"))
+		Expect(client.Timeout).To(Equal(2 * time.Second))
+		Expect(client.Retry.MaxAttempts).To(BeNumerically(">", 1))
+		Expect(client.Language).To(Equal("de"))
+		Expect(client.Debug).To(BeFalse())
+		Expect(client.UserAgent).To(Equal(jokeclient.DefaultUserAgent))
+	})
+
+	It("should send requests through a custom transport", func() {
+		var seen *http.Request
+		client := jokeclient.NewClient(
+			jokeclient.WithBaseURL("http://jokes.invalid/joke"),
+			jokeclient.WithUserAgent("joke-tests/1.0"),
+			jokeclient.WithTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				seen = req
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(jokeJSON)),
+				}, nil
+			})),
+		)
+
+		joke, err := client.FetchJoke("Tell me a pun joke")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke).To(Equal("Served by a round tripper"))
+		Expect(seen.URL.String()).To(Equal("http://jokes.invalid/joke/Pun"))
+		Expect(seen.Header.Get("User-Agent")).To(Equal("joke-tests/1.0"))
+	})
+
+	It("should reuse the injected HTTP client for every request", func() {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(jokeJSON))
+		}))
+		defer server.Close()
+
+		httpClient := server.Client()
+		transport := httpClient.Transport
+		httpClient.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return transport.RoundTrip(req)
+		})
+
+		client := jokeclient.NewClient(
+			jokeclient.WithBaseURL(server.URL),
+			jokeclient.WithHTTPClient(httpClient),
+		)
+
+		for i := 0; i < 3; i++ {
+			_, err := client.FetchJoke("any joke")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(client.HTTPClient).To(BeIdenticalTo(httpClient))
+		Expect(requests).To(Equal(3))
+	})
+})
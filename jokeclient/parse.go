@@ -0,0 +1,305 @@
+package jokeclient
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// negationWords start a run of excluded categories or blacklisted flags
+var negationWords = map[string]bool{
+	"no": true, "not": true, "nothing": true, "without": true, "non": true,
+}
+
+// listWords may appear inside a negated run without ending it
+var listWords = map[string]bool{
+	"or": true, "and": true, "nor": true, "any": true,
+}
+
+// numberWords maps spelled out amounts to their values
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+	"couple": 2, "few": 3,
+}
+
+// topicWords introduce the subject of a search, as in "a joke about coffee"
+var topicWords = map[string]bool{
+	"about": true, "mentioning": true, "involving": true, "featuring": true,
+}
+
+// fillerWords are skipped when looking for the subject of a search
+var fillerWords = map[string]bool{
+	"a": true, "an": true, "the": true, "some": true, "my": true, "our": true, "your": true,
+}
+
+// vagueWords follow topic words without naming a subject
+var vagueWords = map[string]bool{
+	"anything": true, "something": true, "it": true, "that": true, "this": true,
+	"me": true, "you": true, "us": true, "them": true, "joke": true, "jokes": true,
+}
+
+// ParseQuery builds a JokeQuery from a free text request.
+//
+// Explicit parameters such as "category:programming" or the
+// "category=programming&type=single" form are honoured as given. The rest of
+// the text is matched word by word, so "no pun intended" does not select the
+// Pun category and "single" only sets the type when it describes the joke.
+// Languages are recognised in phrases like "in German" or "a French joke",
+// and the subject of "a joke about coffee" becomes the search string.
+// Amounts above MaxAmount are lowered to it, since JokeAPI serves no more.
+func ParseQuery(input string) JokeQuery {
+	query := parseRequest(input)
+	query.Amount = min(query.Amount, MaxAmount)
+	return query
+}
+
+// RequestedAmount returns how many jokes input asks for before ParseQuery
+// limits it to MaxAmount, or zero if it names no amount
+func RequestedAmount(input string) int {
+	return parseRequest(input).Amount
+}
+
+// parseRequest is ParseQuery without the limit on the amount
+func parseRequest(input string) JokeQuery {
+	var query JokeQuery
+	var text []string
+
+	// Pull out explicit key/value parameters first
+	for _, field := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '&'
+	}) {
+		if !applyParam(&query, field) {
+			text = append(text, field)
+		}
+	}
+
+	words := strings.FieldsFunc(strings.Join(text, " "), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+
+	nsfwRequested := false
+	negated := false
+	explicitCategory := len(query.Categories) > 0
+
+	for i, word := range words {
+		next := ""
+		if i+1 < len(words) {
+			next = words[i+1]
+		}
+
+		switch {
+		case query.Amount == 0 && isAmount(word, words[i+1:]):
+			query.Amount = parseAmount(word)
+
+		case negationWords[word]:
+			negated = true
+			continue
+
+		case word == "twopart" || word == "two-part":
+			if query.Type == "" {
+				query.Type = TypeTwoPart
+			}
+
+		case word == "one-liner" || word == "single-part" ||
+			(word == "single" && describesJoke(words[i+1:])):
+			if query.Type == "" {
+				query.Type = TypeSingle
+			}
+
+		case query.Language == "" && isLanguage(word, words[:i], next):
+			query.Language = languageNames[word]
+
+		case query.Contains == "" && topicWords[word]:
+			query.Contains = searchTopic(words[i+1:])
+			query.ContainsGuess = query.Contains != ""
+
+		case (word == "safe" || word == "family-friendly" || word == "kid-friendly") && !negated:
+			query.SafeMode = true
+
+		case word == "clean" && !negated:
+			query.BlacklistFlags = appendUnique(query.BlacklistFlags, "nsfw")
+
+		case word == "dirty" || word == "adult":
+			if !negated {
+				nsfwRequested = true
+			}
+		}
+
+		if flag, ok := lookup(KnownFlags(), word); ok {
+			if negated {
+				query.BlacklistFlags = appendUnique(query.BlacklistFlags, flag)
+			} else if flag == "nsfw" || flag == "explicit" {
+				nsfwRequested = true
+			}
+			continue
+		}
+
+		if category, ok := lookup(KnownCategories(), word); ok {
+			// Collect every requested category; JokeAPI accepts a comma-joined list
+			if !negated && !explicitCategory {
+				query.Categories = appendUnique(query.Categories, category)
+			}
+			continue
+		}
+
+		// Anything other than a list connector ends a negated run
+		if !listWords[word] {
+			negated = false
+		}
+	}
+
+	// Only blacklist NSFW if not explicitly requested
+	if nsfwRequested {
+		query.BlacklistFlags = removeValue(query.BlacklistFlags, "nsfw")
+	}
+
+	return query.Normalize()
+}
+
+// applyParam applies a "key=value" or "key:value" field to the query and
+// reports whether the field was recognised
+func applyParam(query *JokeQuery, field string) bool {
+	key, value, found := strings.Cut(field, "=")
+	if !found {
+		key, value, found = strings.Cut(field, ":")
+	}
+	if !found || value == "" {
+		return false
+	}
+
+	values := strings.Split(value, ",")
+
+	switch key {
+	case "category", "categories":
+		for _, category := range values {
+			if category != "" && !strings.EqualFold(category, "any") {
+				query.Categories = appendUnique(query.Categories, category)
+			}
+		}
+	case "type":
+		query.Type = value
+	case "blacklist", "blacklistflags", "flags":
+		for _, flag := range values {
+			if flag != "" {
+				query.BlacklistFlags = appendUnique(query.BlacklistFlags, flag)
+			}
+		}
+	case "lang", "language":
+		query.Language = value
+	case "amount":
+		if amount, err := strconv.Atoi(value); err == nil {
+			query.Amount = amount
+		}
+	case "contains", "search":
+		query.Contains = value
+	case "safe", "safe-mode", "safemode":
+		safeMode, err := strconv.ParseBool(value)
+		if err != nil {
+			return false
+		}
+		query.SafeMode = safeMode
+	case "id", "idrange":
+		idRange, err := ParseIDRange(value)
+		if err != nil {
+			return false
+		}
+		query.IDRange = idRange
+	default:
+		return false
+	}
+
+	return true
+}
+
+// searchTopic returns the subject named at the start of words, or "" if the
+// words name a category, a flag or nothing in particular
+func searchTopic(words []string) string {
+	for _, word := range words {
+		// Numbers count rather than name the subject, as in "about 2 cats"
+		if _, err := strconv.Atoi(word); fillerWords[word] || err == nil {
+			continue
+		}
+		// A single letter is all that is left of words like "c++"
+		if len([]rune(word)) == 1 {
+			return ""
+		}
+		if vagueWords[word] || negationWords[word] || listWords[word] {
+			return ""
+		}
+		if _, ok := lookup(KnownCategories(), word); ok {
+			return ""
+		}
+		if _, ok := lookup(KnownFlags(), word); ok {
+			return ""
+		}
+		return word
+	}
+	return ""
+}
+
+// isLanguage reports whether word names the language of the joke, as in
+// "in German" or "a German joke"
+func isLanguage(word string, before []string, next string) bool {
+	if _, ok := languageNames[word]; !ok {
+		return false
+	}
+	if len(before) > 0 && before[len(before)-1] == "in" {
+		return true
+	}
+	return next == "joke" || next == "jokes" || next == "pun" || next == "puns"
+}
+
+// removeValue returns list without any occurrence of value
+func removeValue(list []string, value string) []string {
+	result := list[:0:0]
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// isAmount reports whether word is a number that counts the jokes asked for,
+// as in "5 pun jokes" or "a couple of jokes"
+func isAmount(word string, rest []string) bool {
+	if parseAmount(word) == 0 {
+		return false
+	}
+
+	// Look a few words ahead for the plural noun the number applies to
+	for i := 0; i < len(rest) && i < 3; i++ {
+		if rest[i] == "jokes" {
+			return true
+		}
+	}
+	return false
+}
+
+// describesJoke reports whether the word before rest describes the joke, as
+// "single" does in "a single programming joke" or "single part"
+func describesJoke(rest []string) bool {
+	if len(rest) > 0 && rest[0] == "part" {
+		return true
+	}
+
+	// Look a few words ahead for the joke the word applies to
+	for i := 0; i < len(rest) && i < 3; i++ {
+		if rest[i] == "joke" || rest[i] == "jokes" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAmount converts a digit string or number word to its value, or zero
+func parseAmount(word string) int {
+	if amount, ok := numberWords[word]; ok {
+		return amount
+	}
+	if amount, err := strconv.Atoi(word); err == nil && amount > 0 {
+		return amount
+	}
+	return 0
+}
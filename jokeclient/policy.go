@@ -0,0 +1,193 @@
+package jokeclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// Policy is a set of content rules every joke shown must satisfy, on top of
+// the blacklist and safe mode of the query that fetched it
+type Policy struct {
+	BlockedFlags      []string `json:"blockedFlags" yaml:"blockedFlags"`           // Flags no joke may carry
+	BlockedCategories []string `json:"blockedCategories" yaml:"blockedCategories"` // Categories never shown
+	BlockedWords      []string `json:"blockedWords" yaml:"blockedWords"`           // Words no joke may contain
+	RequireSafe       bool     `json:"requireSafe" yaml:"requireSafe"`             // Treat every query as safe mode
+	MaxRefetches      int      `json:"maxRefetches" yaml:"maxRefetches"`           // Attempts before giving up, maxRefetches if zero
+}
+
+// PolicyViolation explains why a joke was rejected
+type PolicyViolation struct {
+	JokeID int
+	Reason string
+}
+
+// Error describes the violation
+func (v *PolicyViolation) Error() string {
+	return fmt.Sprintf("joke %d rejected: %s", v.JokeID, v.Reason)
+}
+
+// Is matches ErrPolicyViolation
+func (v *PolicyViolation) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// LoadPolicy reads a policy from a .json, .yaml or .yml file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	var policy Policy
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &policy)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &policy)
+	default:
+		return nil, fmt.Errorf("unsupported policy format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// Validate checks that the policy names known flags and categories
+func (p *Policy) Validate() error {
+	for _, flag := range p.BlockedFlags {
+		if _, ok := lookup(KnownFlags(), flag); !ok {
+			return fmt.Errorf("invalid blacklist flag: %s", flag)
+		}
+	}
+	for _, category := range p.BlockedCategories {
+		if _, ok := lookup(KnownCategories(), category); !ok {
+			return fmt.Errorf("invalid category: %s", category)
+		}
+	}
+	if p.MaxRefetches < 0 {
+		return fmt.Errorf("maxRefetches must not be negative, got %d", p.MaxRefetches)
+	}
+	return nil
+}
+
+// Check returns a *PolicyViolation if joke breaks the query's blacklist or
+// safe mode, or the policy's own rules. A nil policy only enforces the query.
+func (p *Policy) Check(query JokeQuery, joke model.JokeResponse) error {
+	violation := func(format string, args ...interface{}) error {
+		return &PolicyViolation{JokeID: joke.ID, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	for _, flag := range query.BlacklistFlags {
+		if hasFlag(joke, strings.ToLower(flag)) {
+			return violation("flagged %s, which the request blacklisted", strings.ToLower(flag))
+		}
+	}
+
+	if (query.SafeMode || p != nil && p.RequireSafe) && !isSafe(joke) {
+		return violation("not safe for safe mode")
+	}
+
+	if p == nil {
+		return nil
+	}
+
+	for _, flag := range p.BlockedFlags {
+		if hasFlag(joke, strings.ToLower(flag)) {
+			return violation("flagged %s, which the policy blocks", strings.ToLower(flag))
+		}
+	}
+
+	if _, blocked := lookup(p.BlockedCategories, joke.Category); blocked {
+		return violation("category %s is blocked by the policy", joke.Category)
+	}
+
+	text := strings.ToLower(joke.Joke + " " + joke.Setup + " " + joke.Delivery)
+	for _, word := range p.BlockedWords {
+		if word != "" && strings.Contains(text, strings.ToLower(word)) {
+			return violation("contains blocked word %q", word)
+		}
+	}
+
+	return nil
+}
+
+// apply adds the policy's blocked flags and safe mode to query, so providers
+// can filter jokes before they are checked
+func (p *Policy) apply(query JokeQuery) JokeQuery {
+	if p == nil {
+		return query
+	}
+
+	query.SafeMode = query.SafeMode || p.RequireSafe
+	query.BlacklistFlags = slices.Clone(query.BlacklistFlags)
+	for _, flag := range p.BlockedFlags {
+		query.BlacklistFlags = appendUnique(query.BlacklistFlags, strings.ToLower(flag))
+	}
+	return query
+}
+
+// attempts returns how many jokes may be fetched before giving up
+func (p *Policy) attempts() int {
+	if p == nil || p.MaxRefetches == 0 {
+		return maxRefetches
+	}
+	return p.MaxRefetches
+}
+
+// PolicyProvider enforces a policy on jokes from providers that do not
+// enforce one themselves, refetching rejected jokes up to the policy's limit.
+// The JokeAPI client needs no wrapper: it enforces its own Policy.
+type PolicyProvider struct {
+	Provider JokeProvider
+	Policy   *Policy
+	Log      func(format string, args ...interface{}) // Debug log for rejections, nil to disable
+}
+
+// NewPolicyProvider wraps provider so every joke it serves satisfies policy
+func NewPolicyProvider(provider JokeProvider, policy *Policy) *PolicyProvider {
+	return &PolicyProvider{Provider: provider, Policy: policy}
+}
+
+// Name identifies the wrapped provider
+func (p *PolicyProvider) Name() string {
+	return p.Provider.Name()
+}
+
+// FetchJokeWithQuery fetches a joke from the wrapped provider, refetching
+// jokes that violate the policy unless the joke was asked for by ID
+func (p *PolicyProvider) FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error) {
+	query = p.Policy.apply(query)
+
+	var violation error
+	for attempt := 1; attempt <= p.Policy.attempts(); attempt++ {
+		joke, err := p.Provider.FetchJokeWithQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		if violation = p.Policy.Check(query, *joke); violation == nil {
+			return joke, nil
+		}
+		if p.Log != nil {
+			p.Log("REJECTED from %s: %v\n", joke.Provider, violation)
+		}
+		if query.IDRange.single() {
+			return nil, violation
+		}
+	}
+	return nil, fmt.Errorf("no acceptable joke after %d attempts: %w", p.Policy.attempts(), violation)
+}
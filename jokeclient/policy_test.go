@@ -0,0 +1,242 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+// flaggedWith returns a safe, clean joke carrying only the named flag
+func flaggedWith(flag string) model.JokeResponse {
+	joke := model.JokeResponse{ID: 7, Category: "Misc", Type: "single", Joke: "A joke", Safe: true}
+	switch flag {
+	case "nsfw":
+		joke.Flags.Nsfw = true
+	case "religious":
+		joke.Flags.Religious = true
+	case "political":
+		joke.Flags.Political = true
+	case "racist":
+		joke.Flags.Racist = true
+	case "sexist":
+		joke.Flags.Sexist = true
+	case "explicit":
+		joke.Flags.Explicit = true
+	}
+	return joke
+}
+
+var _ = Describe("Policy", func() {
+	// flagEntries pairs every rule that acts on flags with every flag
+	flagEntries := func() []TableEntry {
+		var entries []TableEntry
+		for _, rule := range []string{"blacklist", "policy", "safe mode", "none"} {
+			for _, flag := range []string{"nsfw", "religious", "political", "racist", "sexist", "explicit"} {
+				entries = append(entries, Entry(rule+" with "+flag, rule, flag))
+			}
+		}
+		return entries
+	}
+
+	DescribeTable("checking flagged jokes",
+		func(rule, flag string) {
+			var policy *jokeclient.Policy
+			var query jokeclient.JokeQuery
+			var reason string
+			switch rule {
+			case "blacklist":
+				query.BlacklistFlags = []string{flag}
+				reason = "flagged " + flag + ", which the request blacklisted"
+			case "policy":
+				policy = &jokeclient.Policy{BlockedFlags: []string{flag}}
+				reason = "flagged " + flag + ", which the policy blocks"
+			case "safe mode":
+				query.SafeMode = true
+				reason = "not safe for safe mode"
+			case "none":
+				policy = &jokeclient.Policy{BlockedCategories: []string{"Dark"}}
+			}
+
+			err := policy.Check(query, flaggedWith(flag))
+
+			if reason == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+				Expect(err).To(MatchError(ContainSubstring(reason)))
+			}
+		},
+		flagEntries(),
+	)
+
+	DescribeTable("applying the policy's other rules",
+		func(policy jokeclient.Policy, joke model.JokeResponse, reason string) {
+			err := policy.Check(jokeclient.JokeQuery{}, joke)
+
+			if reason == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(reason)))
+			}
+		},
+		Entry("blocked category", jokeclient.Policy{BlockedCategories: []string{"dark"}},
+			model.JokeResponse{ID: 1, Category: "Dark", Joke: "Grim", Safe: true}, "category Dark is blocked"),
+		Entry("blocked word in a one-liner", jokeclient.Policy{BlockedWords: []string{"Monday"}},
+			model.JokeResponse{ID: 2, Category: "Misc", Joke: "I hate mondays", Safe: true}, `contains blocked word "Monday"`),
+		Entry("blocked word in a punchline", jokeclient.Policy{BlockedWords: []string{"coffee"}},
+			model.JokeResponse{ID: 3, Category: "Misc", Setup: "Why?", Delivery: "Coffee.", Safe: true}, "blocked word"),
+		Entry("unsafe joke when safety is required", jokeclient.Policy{RequireSafe: true},
+			model.JokeResponse{ID: 4, Category: "Misc", Joke: "Edgy"}, "not safe"),
+		Entry("acceptable joke", jokeclient.Policy{BlockedCategories: []string{"Dark"}, BlockedWords: []string{"coffee"}, RequireSafe: true},
+			model.JokeResponse{ID: 5, Category: "Pun", Joke: "Tea time", Safe: true}, ""),
+	)
+
+	Describe("LoadPolicy", func() {
+		It("should read a YAML policy", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.yaml")
+			Expect(os.WriteFile(path, []byte("blockedFlags: [political, religious]\nblockedCategories: [Dark]\nrequireSafe: true\nmaxRefetches: 5\n"), 0644)).To(Succeed())
+
+			policy, err := jokeclient.LoadPolicy(path)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(Equal(&jokeclient.Policy{
+				BlockedFlags:      []string{"political", "religious"},
+				BlockedCategories: []string{"Dark"},
+				RequireSafe:       true,
+				MaxRefetches:      5,
+			}))
+		})
+
+		It("should read a JSON policy", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.json")
+			Expect(os.WriteFile(path, []byte(`{"blockedWords": ["monday"]}`), 0644)).To(Succeed())
+
+			policy, err := jokeclient.LoadPolicy(path)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.BlockedWords).To(Equal([]string{"monday"}))
+		})
+
+		It("should reject unknown flags", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.json")
+			Expect(os.WriteFile(path, []byte(`{"blockedFlags": ["rude"]}`), 0644)).To(Succeed())
+
+			_, err := jokeclient.LoadPolicy(path)
+
+			Expect(err).To(MatchError(ContainSubstring("invalid blacklist flag: rude")))
+		})
+	})
+
+	Describe("Client", func() {
+		var (
+			server   *httptest.Server
+			requests atomic.Int32
+			rawQuery atomic.Value
+		)
+
+		BeforeEach(func() {
+			requests.Store(0)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rawQuery.Store(r.URL.RawQuery)
+				political := requests.Add(1) < 3
+				fmt.Fprintf(w, `{"error": false, "category": "Misc", "type": "single", "joke": "Joke", "flags": {"political": %t}, "id": %d, "safe": true, "lang": "en"}`, political, requests.Load())
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should refetch jokes the API served despite the blacklist", func() {
+			client := jokeclient.NewClient(jokeclient.WithBaseURL(server.URL))
+
+			joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{BlacklistFlags: []string{"political"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.ID).To(Equal(3))
+			Expect(requests.Load()).To(BeEquivalentTo(3))
+		})
+
+		It("should send the policy's flags and give up after its refetch limit", func() {
+			client := jokeclient.NewClient(
+				jokeclient.WithBaseURL(server.URL),
+				jokeclient.WithPolicy(&jokeclient.Policy{BlockedFlags: []string{"political"}, MaxRefetches: 2}),
+			)
+
+			_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+			var violation *jokeclient.PolicyViolation
+			Expect(errors.As(err, &violation)).To(BeTrue())
+			Expect(violation.JokeID).To(Equal(2))
+			Expect(requests.Load()).To(BeEquivalentTo(2))
+			Expect(rawQuery.Load()).To(Equal("blacklistFlags=political"))
+		})
+	})
+
+	Describe("PolicyProvider", func() {
+		It("should refetch rejected jokes from any provider and log why", func() {
+			local := jokeclient.NewLocalProvider([]model.JokeResponse{
+				{ID: 1, Category: "Dark", Type: "single", Joke: "Grim", Safe: true},
+			})
+			var logged []string
+			provider := jokeclient.NewPolicyProvider(local, &jokeclient.Policy{BlockedCategories: []string{"Dark"}})
+			provider.Log = func(format string, args ...interface{}) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			}
+
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+			Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+			Expect(logged).To(HaveLen(3))
+			Expect(logged[0]).To(ContainSubstring("REJECTED from Local corpus: joke 1 rejected: category Dark is blocked"))
+			Expect(provider.Name()).To(Equal("Local corpus"))
+		})
+
+		It("should pass the policy's flags and safe mode to the provider", func() {
+			local := jokeclient.NewLocalProvider([]model.JokeResponse{
+				{ID: 1, Category: "Misc", Type: "single", Joke: "Flagged", Safe: true, Flags: model.Flags{Political: true}},
+				{ID: 2, Category: "Misc", Type: "single", Joke: "Unsafe"},
+				{ID: 3, Category: "Misc", Type: "single", Joke: "Clean", Safe: true},
+			})
+			var logged []string
+			provider := jokeclient.NewPolicyProvider(local, &jokeclient.Policy{BlockedFlags: []string{"political"}, RequireSafe: true})
+			provider.Log = func(format string, args ...interface{}) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			}
+
+			for i := 0; i < 10; i++ {
+				joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(joke.ID).To(Equal(3))
+			}
+			Expect(logged).To(BeEmpty())
+		})
+
+		It("should not refetch a rejected joke asked for by ID", func() {
+			local := jokeclient.NewLocalProvider([]model.JokeResponse{
+				{ID: 1, Category: "Dark", Type: "single", Joke: "Grim", Safe: true},
+			})
+			var logged []string
+			provider := jokeclient.NewPolicyProvider(local, &jokeclient.Policy{BlockedCategories: []string{"Dark"}})
+			provider.Log = func(format string, args ...interface{}) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			}
+
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{IDRange: &jokeclient.IDRange{From: 1, To: 1}})
+
+			Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+			Expect(logged).To(HaveLen(1))
+		})
+	})
+})
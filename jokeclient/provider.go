@@ -0,0 +1,97 @@
+package jokeclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// ErrUnsupportedQuery is returned by providers that cannot serve a query,
+// e.g. a two-part joke from a source that only has one-liners
+var ErrUnsupportedQuery = errors.New("query not supported by provider")
+
+// JokeProvider is a source of jokes. Client is the JokeAPI implementation;
+// other backends convert their own responses into model.JokeResponse.
+type JokeProvider interface {
+	// Name identifies the provider to users
+	Name() string
+
+	// FetchJokeWithQuery fetches a single joke matching the query and records
+	// the provider's name on it
+	FetchJokeWithQuery(ctx context.Context, query JokeQuery) (*model.JokeResponse, error)
+}
+
+// Name identifies JokeAPI as the provider
+func (c *Client) Name() string {
+	return "JokeAPI"
+}
+
+// httpProvider holds the settings shared by the simple HTTP providers
+type httpProvider struct {
+	BaseURL    string
+	Timeout    time.Duration
+	HTTPClient *http.Client
+	UserAgent  string
+}
+
+// getJSON fetches url and decodes the JSON response into v
+func (p *httpProvider) getJSON(parent context.Context, url string, v any) error {
+	ctx, cancel := context.WithTimeout(parent, p.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.UserAgent != "" {
+		req.Header.Set("User-Agent", p.UserAgent)
+	}
+
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if parent.Err() != nil {
+			return fmt.Errorf("API request failed: %w", err)
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return &transportError{fmt.Errorf("API request timed out after %v seconds", p.Timeout.Seconds())}
+		}
+		return &transportError{fmt.Errorf("API request failed: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &statusError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &transportError{fmt.Errorf("failed to read response body: %w", err)}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return nil
+}
+
+// wantsCategory reports whether a joke in category satisfies the query
+func wantsCategory(query JokeQuery, category string) bool {
+	if len(query.Categories) == 0 {
+		return true
+	}
+	_, ok := lookup(query.Categories, category)
+	return ok
+}
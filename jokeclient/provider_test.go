@@ -0,0 +1,175 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("Joke providers", func() {
+	var server *httptest.Server
+
+	AfterEach(func() {
+		if server != nil {
+			server.Close()
+		}
+	})
+
+	It("should all satisfy the JokeProvider interface", func() {
+		providers := []jokeclient.JokeProvider{
+			jokeclient.NewClient(),
+			jokeclient.NewDadJokeProvider(),
+			jokeclient.NewOfficialJokeProvider(),
+		}
+
+		Expect(providers[0].Name()).To(Equal("JokeAPI"))
+		Expect(providers[1].Name()).To(Equal("icanhazdadjoke"))
+		Expect(providers[2].Name()).To(Equal("Official Joke API"))
+	})
+
+	It("should record JokeAPI as the provider of its jokes", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"error": false, "category": "Misc", "type": "single", "joke": "From JokeAPI", "flags": {}, "id": 1, "safe": true, "lang": "en"}`))
+		}))
+		client := jokeclient.NewClient(jokeclient.WithBaseURL(server.URL))
+
+		joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Provider).To(Equal("JokeAPI"))
+	})
+
+	Describe("DadJokeProvider", func() {
+		var (
+			provider *jokeclient.DadJokeProvider
+			accept   string
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accept = r.Header.Get("Accept")
+				w.Header().Set("Content-Type", "application/json")
+
+				switch {
+				case r.URL.Path == "/":
+					w.Write([]byte(`{"id": "R7UfaahVfFd", "joke": "My dog used to chase people on a bike a lot. It got so bad I had to take his bike away.", "status": 200}`))
+				case r.URL.Path == "/search" && r.URL.Query().Get("term") == "coffee":
+					w.Write([]byte(`{"current_page": 1, "limit": 30, "results": [{"id": "abc", "joke": "How does a coffee bean get famous? It becomes a latte celebrity."}], "total_jokes": 1}`))
+				default:
+					w.Write([]byte(`{"current_page": 1, "limit": 30, "results": [], "total_jokes": 0}`))
+				}
+			}))
+
+			provider = jokeclient.NewDadJokeProvider()
+			provider.BaseURL = server.URL
+		})
+
+		It("should decode a random dad joke", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accept).To(Equal("application/json"))
+			Expect(joke.Type).To(Equal("single"))
+			Expect(joke.Joke).To(ContainSubstring("take his bike away"))
+			Expect(joke.Provider).To(Equal("icanhazdadjoke"))
+			Expect(joke.ID).To(BeZero())
+			Expect(jokeclient.FormatJoke(joke)).To(Equal(joke.Joke))
+		})
+
+		It("should search for jokes containing a term", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Contains: "coffee"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Joke).To(ContainSubstring("latte"))
+		})
+
+		It("should report searches without results as no match", func() {
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Contains: "quantum"})
+
+			Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		})
+
+		It("should reject queries it cannot serve", func() {
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Type: jokeclient.TypeTwoPart})
+			Expect(errors.Is(err, jokeclient.ErrUnsupportedQuery)).To(BeTrue())
+
+			_, err = provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Categories: []string{"Spooky"}})
+			Expect(errors.Is(err, jokeclient.ErrUnsupportedQuery)).To(BeTrue())
+		})
+	})
+
+	Describe("OfficialJokeProvider", func() {
+		var (
+			provider *jokeclient.OfficialJokeProvider
+			path     string
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.URL.Path {
+				case "/random_joke":
+					w.Write([]byte(`{"type": "general", "setup": "What do you call a belt made of watches?", "punchline": "A waist of time.", "id": 42}`))
+				case "/jokes/programming/random":
+					w.Write([]byte(`[{"type": "programming", "setup": "How many programmers does it take to change a lightbulb?", "punchline": "None, that's a hardware problem.", "id": 7}]`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			provider = jokeclient.NewOfficialJokeProvider()
+			provider.BaseURL = server.URL
+		})
+
+		It("should decode a random joke into a twopart joke", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/random_joke"))
+			Expect(joke.Type).To(Equal("twopart"))
+			Expect(joke.Category).To(Equal("Misc"))
+			Expect(joke.Setup).To(Equal("What do you call a belt made of watches?"))
+			Expect(joke.Delivery).To(Equal("A waist of time."))
+			Expect(joke.ID).To(Equal(42))
+			Expect(joke.Provider).To(Equal("Official Joke API"))
+		})
+
+		It("should use the programming endpoint for programming jokes", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Categories: []string{"programming"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/jokes/programming/random"))
+			Expect(joke.Category).To(Equal("Programming"))
+			Expect(joke.Delivery).To(ContainSubstring("hardware problem"))
+		})
+
+		It("should use the programming endpoint when Misc jokes are not wanted", func() {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Categories: []string{"programming", "pun"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/jokes/programming/random"))
+			Expect(joke.Category).To(Equal("Programming"))
+		})
+
+		It("should report a joke outside the requested categories as no match", func() {
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Categories: []string{"pun"}})
+
+			Expect(path).To(Equal("/random_joke"))
+			Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		})
+
+		It("should reject queries it cannot serve", func() {
+			_, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Type: jokeclient.TypeSingle})
+			Expect(errors.Is(err, jokeclient.ErrUnsupportedQuery)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,300 @@
+package jokeclient
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// Vocabulary is the set of categories, flags and languages queries may use
+type Vocabulary struct {
+	Categories []string // Joke categories, without "Any"
+	Flags      []string // Blacklist flags
+	Languages  []string // Language codes jokes are served in
+}
+
+// DefaultVocabulary returns JokeAPI's lists as they were when this package
+// was written, used until Client.Discover finds the current ones
+func DefaultVocabulary() Vocabulary {
+	return Vocabulary{
+		Categories: []string{"Programming", "Misc", "Dark", "Pun", "Spooky", "Christmas"},
+		Flags:      []string{"nsfw", "religious", "political", "racist", "sexist", "explicit"},
+		Languages:  []string{"cs", "de", "en", "es", "fr", "pt"},
+	}
+}
+
+// vocabulary is the vocabulary used to parse and validate queries
+var vocabulary = struct {
+	sync.RWMutex
+	Vocabulary
+}{Vocabulary: DefaultVocabulary()}
+
+// SetVocabulary replaces the vocabulary used to parse and validate queries.
+// Empty lists leave the current ones in place.
+func SetVocabulary(v Vocabulary) {
+	vocabulary.Lock()
+	defer vocabulary.Unlock()
+
+	if len(v.Categories) > 0 {
+		vocabulary.Categories = slices.Clone(v.Categories)
+	}
+	if len(v.Flags) > 0 {
+		vocabulary.Flags = slices.Clone(v.Flags)
+	}
+	if len(v.Languages) > 0 {
+		vocabulary.Languages = slices.Clone(v.Languages)
+	}
+}
+
+// KnownCategories returns the joke categories JokeAPI accepts
+func KnownCategories() []string {
+	vocabulary.RLock()
+	defer vocabulary.RUnlock()
+	return slices.Clone(vocabulary.Categories)
+}
+
+// ParseCategory returns the known category named by s in its canonical
+// spelling, ignoring case
+func ParseCategory(s string) (model.Category, error) {
+	return model.ParseCategory(s, KnownCategories())
+}
+
+// KnownFlags returns the blacklist flags JokeAPI accepts
+func KnownFlags() []string {
+	vocabulary.RLock()
+	defer vocabulary.RUnlock()
+	return slices.Clone(vocabulary.Flags)
+}
+
+// SupportedLanguages returns the language codes JokeAPI serves jokes in
+func SupportedLanguages() []string {
+	vocabulary.RLock()
+	defer vocabulary.RUnlock()
+	return slices.Clone(vocabulary.Languages)
+}
+
+// languageNames maps English and native language names to their codes
+var languageNames = map[string]string{
+	"czech": "cs", "čeština": "cs", "cesky": "cs",
+	"german": "de", "deutsch": "de",
+	"english": "en",
+	"spanish": "es", "español": "es", "espanol": "es",
+	"french": "fr", "français": "fr", "francais": "fr",
+	"portuguese": "pt", "português": "pt", "portugues": "pt",
+}
+
+// LanguageName returns the English name of a supported language code, or the
+// code itself if it is unknown
+func LanguageName(code string) string {
+	switch strings.ToLower(code) {
+	case "cs":
+		return "Czech"
+	case "de":
+		return "German"
+	case "en":
+		return "English"
+	case "es":
+		return "Spanish"
+	case "fr":
+		return "French"
+	case "pt":
+		return "Portuguese"
+	}
+	return code
+}
+
+// Joke types accepted by JokeAPI
+const (
+	TypeSingle  = "single"
+	TypeTwoPart = "twopart"
+)
+
+// MaxAmount is the largest number of jokes JokeAPI returns in one request
+const MaxAmount = 10
+
+// IDRange restricts a query to jokes whose ID lies within [From, To]
+type IDRange struct {
+	From int
+	To   int
+}
+
+// String formats the range the way JokeAPI expects it
+func (r IDRange) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// single reports whether the range names exactly one joke
+func (r *IDRange) single() bool {
+	return r != nil && r.From == r.To
+}
+
+// ParseIDRange parses a joke ID such as "42" or an ID range such as "10-50"
+func ParseIDRange(s string) (*IDRange, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		to = from
+	}
+
+	first, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil || first < 0 {
+		return nil, fmt.Errorf("invalid joke ID: %q", s)
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil || last < 0 {
+		return nil, fmt.Errorf("invalid joke ID: %q", s)
+	}
+
+	idRange := &IDRange{From: first, To: last}
+	if first > last {
+		return nil, fmt.Errorf("invalid ID range: %s", idRange)
+	}
+	return idRange, nil
+}
+
+// JokeQuery describes a joke request in structured form
+type JokeQuery struct {
+	Categories     []string // Empty means any category
+	Type           string   // TypeSingle, TypeTwoPart or empty for either
+	BlacklistFlags []string // Flags the returned joke must not have
+	Language       string   // Language code, empty for the API default
+	Contains       string   // Search string the joke must contain
+	ContainsGuess  bool     // Contains was inferred from free text, so a search may drop it
+	IDRange        *IDRange // Optional ID restriction
+	Amount         int      // Number of jokes, zero means one
+	SafeMode       bool     // Only jokes suitable for everyone, checked again on arrival
+}
+
+// Validate checks the query against the values JokeAPI accepts
+func (q JokeQuery) Validate() error {
+	for _, category := range q.Categories {
+		if _, ok := lookup(KnownCategories(), category); !ok {
+			return fmt.Errorf("%w: %s", model.ErrInvalidCategory, category)
+		}
+	}
+
+	if q.Type != "" && q.Type != TypeSingle && q.Type != TypeTwoPart {
+		return fmt.Errorf("invalid joke type: %s", q.Type)
+	}
+
+	for _, flag := range q.BlacklistFlags {
+		if _, ok := lookup(KnownFlags(), flag); !ok {
+			return fmt.Errorf("invalid blacklist flag: %s", flag)
+		}
+	}
+
+	if q.Language != "" {
+		if _, ok := lookup(SupportedLanguages(), q.Language); !ok {
+			return fmt.Errorf("unsupported language: %s", q.Language)
+		}
+	}
+
+	if q.IDRange != nil {
+		if q.IDRange.From < 0 || q.IDRange.To < q.IDRange.From {
+			return fmt.Errorf("invalid ID range: %s", q.IDRange)
+		}
+	}
+
+	// Zero is the default of one joke
+	if q.Amount < 0 || q.Amount > MaxAmount {
+		return fmt.Errorf("amount must be between 1 and %d, or zero for one joke, got %d", MaxAmount, q.Amount)
+	}
+
+	return nil
+}
+
+// Normalize returns a copy of the query with values in the casing JokeAPI expects
+func (q JokeQuery) Normalize() JokeQuery {
+	normalized := q
+
+	normalized.Categories = nil
+	for _, category := range q.Categories {
+		if canonical, ok := lookup(KnownCategories(), category); ok {
+			category = canonical
+		}
+		normalized.Categories = appendUnique(normalized.Categories, category)
+	}
+
+	normalized.Type = strings.ToLower(q.Type)
+
+	normalized.BlacklistFlags = nil
+	for _, flag := range q.BlacklistFlags {
+		normalized.BlacklistFlags = appendUnique(normalized.BlacklistFlags, strings.ToLower(flag))
+	}
+
+	normalized.Language = strings.ToLower(q.Language)
+	normalized.Contains = strings.TrimSpace(q.Contains)
+
+	return normalized
+}
+
+// path returns the category segment of the request URL
+func (q JokeQuery) path() string {
+	if len(q.Categories) == 0 {
+		return "Any"
+	}
+	return strings.Join(q.Categories, ",")
+}
+
+// encode returns the query string for the request URL, without the leading "?"
+func (q JokeQuery) encode() string {
+	params := []string{}
+
+	if q.SafeMode {
+		params = append(params, "safe-mode")
+	}
+
+	if q.Type != "" {
+		params = append(params, "type="+q.Type)
+	}
+
+	if len(q.BlacklistFlags) > 0 {
+		params = append(params, "blacklistFlags="+strings.Join(q.BlacklistFlags, ","))
+	}
+
+	if q.Language != "" {
+		params = append(params, "lang="+q.Language)
+	}
+
+	if q.Contains != "" {
+		// JokeAPI expects spaces percent-encoded, not as "+"
+		params = append(params, "contains="+strings.ReplaceAll(url.QueryEscape(q.Contains), "+", "%20"))
+	}
+
+	if q.IDRange != nil {
+		params = append(params, "idRange="+q.IDRange.String())
+	}
+
+	if q.Amount > 1 {
+		params = append(params, "amount="+strconv.Itoa(q.Amount))
+	}
+
+	return strings.Join(params, "&")
+}
+
+// lookup finds value in list ignoring case and returns the canonical spelling
+func lookup(list []string, value string) (string, bool) {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return item, true
+		}
+	}
+	return "", false
+}
+
+// appendUnique appends value to list unless it is already present
+func appendUnique(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,310 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("JokeQuery", func() {
+	Describe("Validate", func() {
+		It("should accept an empty query", func() {
+			Expect(jokeclient.JokeQuery{}.Validate()).To(Succeed())
+		})
+
+		It("should accept a fully specified query", func() {
+			query := jokeclient.JokeQuery{
+				Categories:     []string{"Programming"},
+				Type:           jokeclient.TypeTwoPart,
+				BlacklistFlags: []string{"nsfw", "racist"},
+				Language:       "de",
+				Contains:       "coffee",
+				IDRange:        &jokeclient.IDRange{From: 10, To: 50},
+			}
+			Expect(query.Validate()).To(Succeed())
+		})
+
+		It("should reject unknown categories", func() {
+			err := jokeclient.JokeQuery{Categories: []string{"Knock-Knock"}}.Validate()
+			Expect(err).To(MatchError("invalid joke category: Knock-Knock"))
+			Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeTrue())
+		})
+
+		It("should reject unknown joke types", func() {
+			err := jokeclient.JokeQuery{Type: "threepart"}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("invalid joke type")))
+		})
+
+		It("should reject unknown blacklist flags", func() {
+			err := jokeclient.JokeQuery{BlacklistFlags: []string{"boring"}}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("invalid blacklist flag")))
+		})
+
+		It("should reject unsupported languages", func() {
+			err := jokeclient.JokeQuery{Language: "xx"}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("unsupported language")))
+		})
+
+		It("should reject inverted ID ranges", func() {
+			err := jokeclient.JokeQuery{IDRange: &jokeclient.IDRange{From: 50, To: 10}}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("invalid ID range")))
+		})
+
+		It("should reject amounts outside the API limit", func() {
+			err := jokeclient.JokeQuery{Amount: jokeclient.MaxAmount + 1}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("amount must be between")))
+
+			err = jokeclient.JokeQuery{Amount: -1}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("or zero for one joke")))
+		})
+
+		It("should accept a zero amount as one joke", func() {
+			Expect(jokeclient.JokeQuery{}.Validate()).To(Succeed())
+		})
+	})
+
+	Describe("ParseCategory", func() {
+		It("should return the canonical spelling ignoring case", func() {
+			Expect(jokeclient.ParseCategory("programming")).To(Equal(model.CategoryProgramming))
+			Expect(jokeclient.ParseCategory(" CHRISTMAS ")).To(Equal(model.CategoryChristmas))
+		})
+
+		It("should reject unknown categories", func() {
+			_, err := jokeclient.ParseCategory("knock-knock")
+
+			Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeTrue())
+		})
+	})
+
+	Describe("ParseIDRange", func() {
+		It("should parse a single ID", func() {
+			Expect(jokeclient.ParseIDRange("42")).To(Equal(&jokeclient.IDRange{From: 42, To: 42}))
+		})
+
+		It("should parse an ID range", func() {
+			Expect(jokeclient.ParseIDRange("10-50")).To(Equal(&jokeclient.IDRange{From: 10, To: 50}))
+		})
+
+		It("should reject malformed and inverted ranges", func() {
+			_, err := jokeclient.ParseIDRange("ten")
+			Expect(err).To(MatchError(ContainSubstring("invalid joke ID")))
+
+			_, err = jokeclient.ParseIDRange("50-10")
+			Expect(err).To(MatchError(ContainSubstring("invalid ID range: 50-10")))
+		})
+	})
+
+	Describe("LanguageName", func() {
+		It("should name supported languages and pass others through", func() {
+			Expect(jokeclient.LanguageName("de")).To(Equal("German"))
+			Expect(jokeclient.LanguageName("PT")).To(Equal("Portuguese"))
+			Expect(jokeclient.LanguageName("xx")).To(Equal("xx"))
+		})
+	})
+
+	Describe("ParseQuery", func() {
+		It("should pick up categories and types from free text", func() {
+			query := jokeclient.ParseQuery("Tell me a twopart programming joke")
+			Expect(query.Categories).To(Equal([]string{"Programming"}))
+			Expect(query.Type).To(Equal(jokeclient.TypeTwoPart))
+		})
+
+		It("should collect every requested category", func() {
+			query := jokeclient.ParseQuery("Give me a programming or pun joke, nothing dark")
+			Expect(query.Categories).To(Equal([]string{"Programming", "Pun"}))
+		})
+
+		It("should not treat negated categories as requests", func() {
+			query := jokeclient.ParseQuery("Tell me a joke, no pun intended")
+			Expect(query.Categories).To(BeEmpty())
+		})
+
+		It("should only set the single type when it describes the joke", func() {
+			Expect(jokeclient.ParseQuery("I'm single, cheer me up").Type).To(BeEmpty())
+			Expect(jokeclient.ParseQuery("Give me a single joke").Type).To(Equal(jokeclient.TypeSingle))
+			Expect(jokeclient.ParseQuery("a single programming joke").Type).To(Equal(jokeclient.TypeSingle))
+			Expect(jokeclient.ParseQuery("a single pun joke").Type).To(Equal(jokeclient.TypeSingle))
+		})
+
+		It("should blacklist every flag in a negated list", func() {
+			query := jokeclient.ParseQuery("Tell me a joke but nothing nsfw or political")
+			Expect(query.BlacklistFlags).To(ConsistOf("nsfw", "political"))
+		})
+
+		It("should not blacklist nsfw when it is requested", func() {
+			query := jokeclient.ParseQuery("Tell me a dirty joke, nothing racist")
+			Expect(query.BlacklistFlags).To(ConsistOf("racist"))
+		})
+
+		It("should parse the key=value form produced by the LLM parser", func() {
+			query := jokeclient.ParseQuery("category=programming&type=single&blacklist=religious,political")
+			Expect(query.Categories).To(Equal([]string{"Programming"}))
+			Expect(query.Type).To(Equal(jokeclient.TypeSingle))
+			Expect(query.BlacklistFlags).To(Equal([]string{"religious", "political"}))
+		})
+
+		It("should parse comma separated categories from the LLM parser", func() {
+			query := jokeclient.ParseQuery("category=programming,pun&type=twopart")
+			Expect(query.Categories).To(Equal([]string{"Programming", "Pun"}))
+		})
+
+		It("should pick up the number of jokes requested", func() {
+			Expect(jokeclient.ParseQuery("give me 5 pun jokes").Amount).To(Equal(5))
+			Expect(jokeclient.ParseQuery("tell me three programming jokes").Amount).To(Equal(3))
+			Expect(jokeclient.ParseQuery("tell me a couple of jokes").Amount).To(Equal(2))
+			Expect(jokeclient.ParseQuery("my 3 kids want a joke").Amount).To(BeZero())
+		})
+
+		It("should limit amounts to what JokeAPI serves at once", func() {
+			query := jokeclient.ParseQuery("give me 20 jokes")
+			Expect(query.Amount).To(Equal(jokeclient.MaxAmount))
+			Expect(query.Validate()).To(Succeed())
+			Expect(jokeclient.ParseQuery("amount=15").Amount).To(Equal(jokeclient.MaxAmount))
+
+			Expect(jokeclient.RequestedAmount("give me 20 jokes")).To(Equal(20))
+			Expect(jokeclient.RequestedAmount("tell me a joke")).To(BeZero())
+		})
+
+		It("should pick up the language the joke is wanted in", func() {
+			Expect(jokeclient.ParseQuery("tell me a programming joke in German").Language).To(Equal("de"))
+			Expect(jokeclient.ParseQuery("a French joke please").Language).To(Equal("fr"))
+			Expect(jokeclient.ParseQuery("un chiste en español").Language).To(BeEmpty())
+			Expect(jokeclient.ParseQuery("a joke in español").Language).To(Equal("es"))
+			Expect(jokeclient.ParseQuery("a joke about my german shepherd").Language).To(BeEmpty())
+			Expect(jokeclient.ParseQuery("category=pun&lang=pt").Language).To(Equal("pt"))
+		})
+
+		It("should search for the topic of the joke", func() {
+			Expect(jokeclient.ParseQuery("tell me a joke about coffee").Contains).To(Equal("coffee"))
+			Expect(jokeclient.ParseQuery("a pun involving the weather").Contains).To(Equal("weather"))
+			Expect(jokeclient.ParseQuery("contains:coffee").Contains).To(Equal("coffee"))
+		})
+
+		It("should only let searches guessed from free text be dropped", func() {
+			query := jokeclient.ParseQuery("I'd like something funny about computers, but keep it clean")
+			Expect(query.Contains).To(Equal("computers"))
+			Expect(query.ContainsGuess).To(BeTrue())
+
+			Expect(jokeclient.ParseQuery("contains:coffee").ContainsGuess).To(BeFalse())
+		})
+
+		It("should skip numbers and stubs when looking for the topic", func() {
+			Expect(jokeclient.ParseQuery("a joke about 2 cats").Contains).To(Equal("cats"))
+			Expect(jokeclient.ParseQuery("a joke about c++").Contains).To(BeEmpty())
+			Expect(jokeclient.ParseQuery("a joke about 42").Contains).To(BeEmpty())
+		})
+
+		It("should not search for categories or vague topics", func() {
+			query := jokeclient.ParseQuery("tell me a joke about programming")
+			Expect(query.Contains).To(BeEmpty())
+			Expect(query.Categories).To(Equal([]string{"Programming"}))
+
+			Expect(jokeclient.ParseQuery("what about a joke").Contains).To(BeEmpty())
+			Expect(jokeclient.ParseQuery("a joke about nothing nsfw").Contains).To(BeEmpty())
+		})
+
+		It("should parse ID ranges from the LLM parser", func() {
+			Expect(jokeclient.ParseQuery("category=programming&idrange=10-50").IDRange).To(Equal(&jokeclient.IDRange{From: 10, To: 50}))
+			Expect(jokeclient.ParseQuery("id:7").IDRange).To(Equal(&jokeclient.IDRange{From: 7, To: 7}))
+		})
+
+		It("should parse explicit key:value parameters", func() {
+			query := jokeclient.ParseQuery("Tell me a joke category:pun type:twopart")
+			Expect(query.Categories).To(Equal([]string{"Pun"}))
+			Expect(query.Type).To(Equal(jokeclient.TypeTwoPart))
+		})
+	})
+
+	Describe("FetchJokeWithQuery", func() {
+		var (
+			client     *jokeclient.Client
+			server     *httptest.Server
+			requestURI string
+		)
+
+		BeforeEach(func() {
+			requestURI = ""
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestURI = r.URL.RequestURI()
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{
+					"error": false,
+					"category": "Programming",
+					"type": "twopart",
+					"setup": "Why do Java developers wear glasses?",
+					"delivery": "Because they don't C#.",
+					"flags": {},
+					"id": 7,
+					"safe": true,
+					"lang": "en"
+				}`))
+			}))
+
+			client = jokeclient.NewClient()
+			client.BaseURL = server.URL
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should build the request URL from the query", func() {
+			joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{
+				Categories:     []string{"programming"},
+				Type:           jokeclient.TypeTwoPart,
+				BlacklistFlags: []string{"nsfw"},
+				Language:       "en",
+				Contains:       "java developers",
+				IDRange:        &jokeclient.IDRange{From: 1, To: 100},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.ID).To(Equal(7))
+			Expect(joke.Setup).To(Equal("Why do Java developers wear glasses?"))
+			Expect(requestURI).To(Equal("/Programming?type=twopart&blacklistFlags=nsfw&lang=en&contains=java%20developers&idRange=1-100"))
+		})
+
+		It("should percent-encode the search string", func() {
+			_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Contains: "R&D costs+1"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestURI).To(Equal("/Any?contains=R%26D%20costs%2B1"))
+		})
+
+		It("should join multiple categories into one path segment", func() {
+			joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{
+				Categories: []string{"programming", "pun"},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Category).To(Equal("Programming"))
+			Expect(requestURI).To(Equal("/Programming,Pun"))
+		})
+
+		It("should use the client's language when the query names none", func() {
+			client.Language = "de"
+
+			_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestURI).To(Equal("/Any?lang=de"))
+
+			_, err = client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Language: "fr"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestURI).To(Equal("/Any?lang=fr"))
+		})
+
+		It("should reject invalid queries without calling the API", func() {
+			_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Type: "threepart"})
+
+			Expect(err).To(MatchError(ContainSubstring("invalid query")))
+			Expect(requestURI).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,130 @@
+package jokeclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExhausted is returned by a fail-fast RateLimiter when no requests
+// are left in the current window
+var ErrQuotaExhausted = errors.New("joke API quota exhausted")
+
+// rateLimitWindow is how long JokeAPI's rate limit windows last. It is used
+// to start a new window when the reported one has passed, until the next
+// response reports the real one.
+const rateLimitWindow = time.Minute
+
+// Quota is the request budget JokeAPI reported in its RateLimit headers
+type Quota struct {
+	Known     bool      // False until a response carried RateLimit headers
+	Limit     int       // Requests allowed per window
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the window resets
+}
+
+// String formats the quota for display
+func (q Quota) String() string {
+	if !q.Known {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d/%d", q.Remaining, q.Limit)
+}
+
+// RateLimiter paces requests using the RateLimit-* headers from previous
+// responses. It is safe for concurrent use by callers sharing one client.
+type RateLimiter struct {
+	FailFast bool // Return ErrQuotaExhausted instead of waiting for the reset
+
+	mu    sync.Mutex
+	quota Quota
+	now   func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter that blocks until the quota resets,
+// or fails fast if failFast is set
+func NewRateLimiter(failFast bool) *RateLimiter {
+	return &RateLimiter{
+		FailFast: failFast,
+		now:      time.Now,
+	}
+}
+
+// Wait reserves one request from the quota, blocking until the window resets
+// when the quota is exhausted
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+
+		// A window that has passed, or one we know nothing about, is open
+		if !l.quota.Known || l.quota.Remaining > 0 || !now.Before(l.quota.Reset) {
+			if l.quota.Known {
+				if !now.Before(l.quota.Reset) {
+					// Start the next window, or every caller would get a fresh budget
+					l.quota.Remaining = l.quota.Limit
+					l.quota.Reset = now.Add(rateLimitWindow)
+				}
+				l.quota.Remaining--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := l.quota.Reset.Sub(now)
+		l.mu.Unlock()
+
+		if l.FailFast {
+			return fmt.Errorf("%w, resets in %v", ErrQuotaExhausted, wait.Round(time.Second))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Update records the quota reported in a response's headers
+func (l *RateLimiter) Update(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.quota.Known = true
+	l.quota.Remaining = remaining
+
+	if limit, err := strconv.Atoi(header.Get("RateLimit-Limit")); err == nil {
+		l.quota.Limit = limit
+	} else if l.quota.Limit < remaining {
+		l.quota.Limit = remaining
+	}
+
+	// RateLimit-Reset is normally seconds until reset, but accept a Unix timestamp too
+	if reset, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		if reset > 1_000_000_000 {
+			l.quota.Reset = time.Unix(reset, 0)
+		} else {
+			l.quota.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+}
+
+// Quota returns the most recently reported quota
+func (l *RateLimiter) Quota() Quota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.quota
+}
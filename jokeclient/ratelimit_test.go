@@ -0,0 +1,134 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("RateLimiter", func() {
+	headers := func(limit, remaining, reset string) http.Header {
+		h := http.Header{}
+		h.Set("RateLimit-Limit", limit)
+		h.Set("RateLimit-Remaining", remaining)
+		h.Set("RateLimit-Reset", reset)
+		return h
+	}
+
+	It("should start with an unknown quota that never blocks", func() {
+		limiter := jokeclient.NewRateLimiter(true)
+
+		Expect(limiter.Quota().Known).To(BeFalse())
+		Expect(limiter.Wait(context.Background())).To(Succeed())
+	})
+
+	It("should parse the RateLimit headers", func() {
+		limiter := jokeclient.NewRateLimiter(true)
+		limiter.Update(headers("120", "87", "30"))
+
+		quota := limiter.Quota()
+		Expect(quota.Known).To(BeTrue())
+		Expect(quota.Limit).To(Equal(120))
+		Expect(quota.Remaining).To(Equal(87))
+		Expect(quota.Reset).To(BeTemporally("~", time.Now().Add(30*time.Second), time.Second))
+		Expect(quota.String()).To(Equal("87/120"))
+	})
+
+	It("should fail fast when the quota is exhausted", func() {
+		limiter := jokeclient.NewRateLimiter(true)
+		limiter.Update(headers("120", "0", "30"))
+
+		err := limiter.Wait(context.Background())
+		Expect(errors.Is(err, jokeclient.ErrQuotaExhausted)).To(BeTrue())
+	})
+
+	It("should block until the window resets", func() {
+		limiter := jokeclient.NewRateLimiter(false)
+		limiter.Update(headers("120", "0", "1"))
+
+		start := time.Now()
+		Expect(limiter.Wait(context.Background())).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically(">=", 900*time.Millisecond))
+		Expect(limiter.Quota().Remaining).To(Equal(119))
+	})
+
+	It("should start a new window once the reported one has passed", func() {
+		limiter := jokeclient.NewRateLimiter(true)
+		limiter.Update(headers("2", "1", strconv.FormatInt(time.Now().Add(-10*time.Second).Unix(), 10)))
+
+		var granted int
+		for i := 0; i < 6; i++ {
+			if limiter.Wait(context.Background()) == nil {
+				granted++
+			}
+		}
+
+		Expect(granted).To(Equal(2))
+		quota := limiter.Quota()
+		Expect(quota.String()).To(Equal("0/2"))
+		Expect(quota.Reset).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+	})
+
+	It("should stop waiting when the context is cancelled", func() {
+		limiter := jokeclient.NewRateLimiter(false)
+		limiter.Update(headers("120", "0", "30"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		Expect(limiter.Wait(ctx)).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should share the remaining budget between concurrent callers", func() {
+		limiter := jokeclient.NewRateLimiter(true)
+		limiter.Update(headers("120", "3", "30"))
+
+		var granted atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if limiter.Wait(context.Background()) == nil {
+					granted.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		Expect(granted.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should expose the quota through the client", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("RateLimit-Limit", "120")
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", "30")
+			w.Write([]byte(`{"error": false, "category": "Misc", "type": "single", "joke": "Last one", "flags": {}, "id": 1, "safe": true, "lang": "en"}`))
+		}))
+		defer server.Close()
+
+		client := jokeclient.NewClient()
+		client.BaseURL = server.URL
+		client.Limiter = jokeclient.NewRateLimiter(true)
+
+		Expect(client.Quota().Known).To(BeFalse())
+
+		_, err := client.FetchJoke("any joke")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Quota().String()).To(Equal("0/120"))
+
+		_, err = client.FetchJoke("any joke")
+		Expect(errors.Is(err, jokeclient.ErrQuotaExhausted)).To(BeTrue())
+	})
+})
@@ -0,0 +1,129 @@
+package jokeclient
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// RetryPolicy controls how failed requests are retried. The zero value
+// disables retries.
+type RetryPolicy struct {
+	MaxAttempts    int           // Total attempts including the first one
+	InitialBackoff time.Duration // Wait before the first retry
+	MaxBackoff     time.Duration // Upper bound for a single wait
+	Multiplier     float64       // Growth factor between waits
+	Jitter         float64       // Fraction of each wait that is randomised, 0 to 1
+	MaxElapsed     time.Duration // Total time budget across all attempts, zero for none
+}
+
+// DefaultRetryPolicy returns a policy suited to JokeAPI's rate limits
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsed:     15 * time.Second,
+	}
+}
+
+// backoff returns the wait before the given retry, starting at 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := float64(p.baseBackoff(retry))
+
+	// Spread the wait by up to ±Jitter so concurrent clients don't retry in lockstep
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(wait)
+}
+
+// longestBackoff returns the longest wait backoff may pick for a retry
+func (p RetryPolicy) longestBackoff(retry int) time.Duration {
+	return time.Duration(float64(p.baseBackoff(retry)) * (1 + max(p.Jitter, 0)))
+}
+
+// baseBackoff returns the wait before a retry without jitter
+func (p RetryPolicy) baseBackoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	wait := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		wait *= multiplier
+	}
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	return time.Duration(wait)
+}
+
+// statusError reports a non-2xx response without an API error payload
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("API request failed with status code: %d", e.StatusCode)
+}
+
+// transportError reports a request that never got a response
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// retryable reports whether a failed attempt is worth repeating
+func retryable(err error) bool {
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	var apiErr *model.APIError
+	if errors.As(err, &apiErr) {
+		return errors.Is(apiErr, model.ErrRateLimited) || apiErr.InternalError || apiErr.StatusCode >= 500
+	}
+
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil && when.After(now) {
+		return when.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,142 @@
+package jokeclient_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("Retry policy", func() {
+	var (
+		client   *jokeclient.Client
+		server   *httptest.Server
+		attempts atomic.Int32
+		handler  func(w http.ResponseWriter, attempt int32)
+	)
+
+	const jokeJSON = `{"error": false, "category": "Misc", "type": "single", "joke": "Third time lucky", "flags": {}, "id": 5, "safe": true, "lang": "en"}`
+
+	BeforeEach(func() {
+		attempts.Store(0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, attempts.Add(1))
+		}))
+
+		client = jokeclient.NewClient()
+		client.BaseURL = server.URL
+		client.Retry = jokeclient.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     50 * time.Millisecond,
+			Multiplier:     2,
+			Jitter:         0.5,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should retry server errors until the request succeeds", func() {
+		handler = func(w http.ResponseWriter, attempt int32) {
+			if attempt < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(jokeJSON))
+		}
+
+		joke, err := client.FetchJoke("any joke")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke).To(Equal("Third time lucky"))
+		Expect(attempts.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		handler = func(w http.ResponseWriter, attempt int32) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		_, err := client.FetchJoke("any joke")
+
+		Expect(err).To(MatchError(ContainSubstring("status code: 500")))
+		Expect(attempts.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should not retry client errors", func() {
+		handler = func(w http.ResponseWriter, attempt int32) {
+			w.Write([]byte(`{"error": true, "code": 106, "message": "No matching joke found"}`))
+		}
+
+		_, err := client.FetchJoke("any joke")
+
+		Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should honour Retry-After on rate limited responses", func() {
+		handler = func(w http.ResponseWriter, attempt int32) {
+			if attempt == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(jokeJSON))
+		}
+
+		start := time.Now()
+		_, err := client.FetchJoke("any joke")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+		Expect(attempts.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should stop when Retry-After exceeds the elapsed budget", func() {
+		client.Retry.MaxElapsed = 200 * time.Millisecond
+		handler = func(w http.ResponseWriter, attempt int32) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+
+		start := time.Now()
+		_, err := client.FetchJoke("any joke")
+
+		Expect(errors.Is(err, model.ErrRateLimited)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should not retry when no policy is configured", func() {
+		client.Retry = jokeclient.RetryPolicy{}
+		handler = func(w http.ResponseWriter, attempt int32) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_, err := client.FetchJoke("any joke")
+
+		Expect(err).To(HaveOccurred())
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+
+	DescribeTable("bounding how long a request may take",
+		func(timeout time.Duration, retry jokeclient.RetryPolicy, longest time.Duration) {
+			client := jokeclient.NewClient(jokeclient.WithTimeout(timeout), jokeclient.WithRetryPolicy(retry))
+
+			Expect(client.MaxRequestTime()).To(Equal(longest))
+		},
+		Entry("one attempt", 5*time.Second, jokeclient.RetryPolicy{}, 5*time.Second),
+		Entry("every attempt with the longest backoff", 2*time.Second,
+			jokeclient.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, Multiplier: 2, Jitter: 0.5}, 10500*time.Millisecond),
+		Entry("the budget and the last attempt", 5*time.Second, jokeclient.DefaultRetryPolicy(), 20*time.Second),
+	)
+})
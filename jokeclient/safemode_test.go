@@ -0,0 +1,144 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("Safe mode", func() {
+	const (
+		unsafeJoke  = `{"error": false, "category": "Dark", "type": "single", "joke": "Unsafe", "flags": {"explicit": true}, "id": 1, "safe": false, "lang": "en"}`
+		flaggedJoke = `{"error": false, "category": "Misc", "type": "single", "joke": "Flagged", "flags": {"political": true}, "id": 2, "safe": true, "lang": "en"}`
+		safeJoke    = `{"error": false, "category": "Pun", "type": "single", "joke": "Safe", "flags": {}, "id": 3, "safe": true, "lang": "en"}`
+	)
+
+	var (
+		server    *httptest.Server
+		responses []string
+		requests  atomic.Int32
+		rawQuery  atomic.Value
+		client    *jokeclient.Client
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawQuery.Store(r.URL.RawQuery)
+			n := int(requests.Add(1))
+			w.Write([]byte(responses[min(n, len(responses))-1]))
+		}))
+		client = jokeclient.NewClient(jokeclient.WithBaseURL(server.URL), jokeclient.WithSafeMode(true))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should ask the API for safe jokes", func() {
+		responses = []string{safeJoke}
+
+		_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{Type: jokeclient.TypeSingle})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rawQuery.Load()).To(Equal("safe-mode&type=single"))
+	})
+
+	It("should refetch jokes that are unsafe or flagged", func() {
+		responses = []string{unsafeJoke, flaggedJoke, safeJoke}
+
+		joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Joke).To(Equal("Safe"))
+		Expect(requests.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should give up when the API keeps serving unsafe jokes", func() {
+		responses = []string{unsafeJoke}
+
+		_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+		Expect(requests.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should not refetch an unsafe joke asked for by ID", func() {
+		responses = []string{unsafeJoke, safeJoke}
+
+		_, err := client.FetchJokeByID(context.Background(), 1)
+
+		var violation *jokeclient.PolicyViolation
+		Expect(errors.As(err, &violation)).To(BeTrue())
+		Expect(violation.JokeID).To(Equal(1))
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should only cache jokes that passed the checks", func() {
+		responses = []string{unsafeJoke}
+		client.Cache = jokeclient.NewMemoryCache(10)
+
+		_, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+		Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+		_, err = client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+		Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+		Expect(requests.Load()).To(BeEquivalentTo(6))
+
+		responses = []string{safeJoke}
+		requests.Store(0)
+		for i := 0; i < 2; i++ {
+			joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Joke).To(Equal("Safe"))
+		}
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should drop unsafe jokes from batches", func() {
+		responses = []string{`{"error": false, "amount": 2, "jokes": [` + unsafeJoke + `,` + safeJoke + `]}`}
+
+		jokes, err := client.FetchJokes(context.Background(), jokeclient.JokeQuery{Amount: 2})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jokes).To(HaveLen(1))
+		Expect(jokes[0].Joke).To(Equal("Safe"))
+	})
+
+	It("should accept any joke when safe mode is off", func() {
+		responses = []string{unsafeJoke}
+		client.SafeMode = false
+
+		joke, err := client.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Joke).To(Equal("Unsafe"))
+		Expect(rawQuery.Load()).To(BeEmpty())
+	})
+
+	It("should only serve safe jokes from the local corpus", func() {
+		provider := jokeclient.NewLocalProvider([]model.JokeResponse{
+			{ID: 1, Type: "single", Joke: "Unsafe", Safe: false},
+			{ID: 2, Type: "single", Joke: "Safe", Safe: true},
+		})
+
+		for i := 0; i < 10; i++ {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{SafeMode: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Joke).To(Equal("Safe"))
+		}
+	})
+
+	It("should be requested in free text", func() {
+		Expect(jokeclient.ParseQuery("a family-friendly pun").SafeMode).To(BeTrue())
+		Expect(jokeclient.ParseQuery("category=pun&safe=true").SafeMode).To(BeTrue())
+		Expect(jokeclient.ParseQuery("a pun that is not safe").SafeMode).To(BeFalse())
+	})
+})
@@ -0,0 +1,58 @@
+package jokeclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// broadenedQueries returns the queries to try, in order, for a search that
+// may find nothing: the query as given, then without its categories, then
+// without its type as well. A search string inferred from free text is
+// finally dropped, keeping the rest of the query. Queries without a search
+// string are not broadened.
+func broadenedQueries(query JokeQuery) []JokeQuery {
+	queries := []JokeQuery{query}
+	if query.Contains == "" {
+		return queries
+	}
+	unsearched := query
+
+	if len(query.Categories) > 0 {
+		query.Categories = nil
+		queries = append(queries, query)
+	}
+
+	if query.Type != "" {
+		query.Type = ""
+		queries = append(queries, query)
+	}
+
+	if unsearched.ContainsGuess {
+		unsearched.Contains = ""
+		unsearched.ContainsGuess = false
+		queries = append(queries, unsearched)
+	}
+
+	return queries
+}
+
+// SearchWithFallback fetches a joke for query from provider. When a search
+// string finds nothing, the search is retried in any category and then of
+// any type, and a search string guessed from free text is dropped. It returns the query that produced the joke, so callers can tell
+// the user which filters were dropped.
+func SearchWithFallback(ctx context.Context, provider JokeProvider, query JokeQuery) (*model.JokeResponse, JokeQuery, error) {
+	var err error
+	for _, attempt := range broadenedQueries(query) {
+		var joke *model.JokeResponse
+		joke, err = provider.FetchJokeWithQuery(ctx, attempt)
+		if err == nil {
+			return joke, attempt, nil
+		}
+		if !errors.Is(err, model.ErrNoMatch) || ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, query, err
+}
@@ -0,0 +1,112 @@
+package jokeclient_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+// searchProvider only finds jokes for queries accepted by match
+type searchProvider struct {
+	match   func(jokeclient.JokeQuery) bool
+	queries []jokeclient.JokeQuery
+}
+
+func (s *searchProvider) Name() string {
+	return "Search"
+}
+
+func (s *searchProvider) FetchJokeWithQuery(ctx context.Context, query jokeclient.JokeQuery) (*model.JokeResponse, error) {
+	s.queries = append(s.queries, query)
+	if !s.match(query) {
+		return nil, &model.APIError{Code: model.CodeNoMatch, Message: "No matching joke found"}
+	}
+	return &model.JokeResponse{Type: "single", Joke: "Decaf? No thanks.", Category: "Misc"}, nil
+}
+
+var _ = Describe("SearchWithFallback", func() {
+	query := jokeclient.JokeQuery{
+		Categories: []string{"Pun"},
+		Type:       jokeclient.TypeTwoPart,
+		Contains:   "coffee",
+	}
+
+	It("should use the query as given when it matches", func() {
+		provider := &searchProvider{match: func(jokeclient.JokeQuery) bool { return true }}
+
+		_, used, err := jokeclient.SearchWithFallback(context.Background(), provider, query)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(used).To(Equal(query))
+		Expect(provider.queries).To(HaveLen(1))
+	})
+
+	It("should search every category when the requested ones have no match", func() {
+		provider := &searchProvider{match: func(q jokeclient.JokeQuery) bool { return len(q.Categories) == 0 }}
+
+		joke, used, err := jokeclient.SearchWithFallback(context.Background(), provider, query)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joke.Joke).To(Equal("Decaf? No thanks."))
+		Expect(used.Categories).To(BeEmpty())
+		Expect(used.Type).To(Equal(jokeclient.TypeTwoPart))
+		Expect(used.Contains).To(Equal("coffee"))
+	})
+
+	It("should drop the type as a last resort", func() {
+		provider := &searchProvider{match: func(q jokeclient.JokeQuery) bool { return q.Type == "" }}
+
+		_, used, err := jokeclient.SearchWithFallback(context.Background(), provider, query)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(used.Categories).To(BeEmpty())
+		Expect(used.Type).To(BeEmpty())
+		Expect(provider.queries).To(HaveLen(3))
+	})
+
+	It("should report no match once every broadened search fails", func() {
+		provider := &searchProvider{match: func(jokeclient.JokeQuery) bool { return false }}
+
+		_, _, err := jokeclient.SearchWithFallback(context.Background(), provider, query)
+
+		Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		Expect(provider.queries).To(HaveLen(3))
+	})
+
+	It("should finally drop a search string guessed from free text", func() {
+		provider := &searchProvider{match: func(q jokeclient.JokeQuery) bool { return q.Contains == "" }}
+		guessed := query
+		guessed.ContainsGuess = true
+
+		_, used, err := jokeclient.SearchWithFallback(context.Background(), provider, guessed)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider.queries).To(HaveLen(4))
+		Expect(used.Contains).To(BeEmpty())
+		Expect(used.Categories).To(Equal([]string{"Pun"}))
+		Expect(used.Type).To(Equal(jokeclient.TypeTwoPart))
+	})
+
+	It("should not broaden queries without a search string", func() {
+		provider := &searchProvider{match: func(jokeclient.JokeQuery) bool { return false }}
+
+		_, _, err := jokeclient.SearchWithFallback(context.Background(), provider, jokeclient.JokeQuery{Categories: []string{"Pun"}})
+
+		Expect(err).To(HaveOccurred())
+		Expect(provider.queries).To(HaveLen(1))
+	})
+
+	It("should not broaden after other errors", func() {
+		stub := &stubProvider{name: "Down", err: errors.New("connection refused")}
+
+		_, _, err := jokeclient.SearchWithFallback(context.Background(), stub, query)
+
+		Expect(err).To(MatchError("connection refused"))
+		Expect(stub.calls).To(Equal(1))
+	})
+})
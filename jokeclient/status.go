@@ -0,0 +1,126 @@
+package jokeclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// Status is a health report on JokeAPI as seen by the client
+type Status struct {
+	Latency time.Duration       // Round trip of the ping
+	Err     error               // Why the API is unhealthy, nil if it answered
+	Info    *model.InfoResponse // What the API serves, nil if it could not be fetched
+	Quota   Quota               // Request budget reported with the ping
+	Breaker BreakerState        // State of the client's circuit breaker
+}
+
+// Healthy reports whether the API answered and jokes can be requested
+// without waiting for the rate limit window to reset
+func (s Status) Healthy() bool {
+	return s.Err == nil && !(s.Quota.Known && s.Quota.Remaining == 0)
+}
+
+// Ping checks that JokeAPI is answering and returns the round trip time. It
+// bypasses the retry policy and circuit breaker so the answer is current, and
+// the rate limiter so an exhausted quota is reported instead of waited out.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	url := c.apiURL("/ping")
+	if c.Debug {
+		c.writeDebug("REQUEST: %s\n", url)
+	}
+
+	start := time.Now()
+	body, _, err := c.do(ctx, http.MethodGet, url, nil)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, fmt.Errorf("ping failed: %w", err)
+	}
+
+	var resp model.PingResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return latency, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if resp.Ping == "" {
+		return latency, fmt.Errorf("ping failed: unexpected reply %s", body)
+	}
+	return latency, nil
+}
+
+// Status pings JokeAPI and gathers what it serves and the remaining budget.
+// The info is only fetched once the ping shows the API is answering and has
+// budget left, so a down or exhausted API is reported without waiting.
+func (c *Client) Status(ctx context.Context) Status {
+	var status Status
+	status.Latency, status.Err = c.Ping(ctx)
+	status.Quota = c.Quota()
+	if status.Healthy() {
+		status.Info, status.Err = c.Info(ctx)
+		status.Quota = c.Quota()
+	}
+	status.Breaker = c.BreakerState()
+	return status
+}
+
+// Report formats the status for display, one fact per line. JokeAPI does
+// not count jokes per category, so counts are given per language.
+func (s Status) Report() string {
+	var lines []string
+
+	switch {
+	case s.Err != nil:
+		lines = append(lines, fmt.Sprintf("JokeAPI: unhealthy: %v", s.Err))
+	case !s.Healthy():
+		lines = append(lines, fmt.Sprintf("JokeAPI: unhealthy: rate limit exhausted (ping %v)", s.Latency.Round(time.Millisecond)))
+	default:
+		lines = append(lines, fmt.Sprintf("JokeAPI: healthy (ping %v)", s.Latency.Round(time.Millisecond)))
+	}
+
+	if info := s.Info; info != nil {
+		lines = append(lines, "Version: "+info.Version)
+
+		languages := make([]string, 0, len(info.Jokes.IDRange))
+		for language := range info.Jokes.IDRange {
+			languages = append(languages, language)
+		}
+		slices.Sort(languages)
+
+		counts := make([]string, len(languages))
+		for i, language := range languages {
+			idRange := info.Jokes.IDRange[language]
+			counts[i] = fmt.Sprintf("%s %d", language, idRange[1]-idRange[0]+1)
+		}
+		lines = append(lines, fmt.Sprintf("Jokes: %d (%s)", info.Jokes.TotalCount, strings.Join(counts, ", ")))
+
+		safe := make([]string, len(info.Jokes.SafeJokes))
+		for i, count := range info.Jokes.SafeJokes {
+			safe[i] = fmt.Sprintf("%s %d", count.Lang, count.Count)
+		}
+		if len(safe) > 0 {
+			lines = append(lines, "Safe jokes: "+strings.Join(safe, ", "))
+		}
+
+		categories := slices.DeleteFunc(slices.Clone(info.Jokes.Categories), func(category string) bool {
+			return strings.EqualFold(category, "any")
+		})
+		lines = append(lines, "Categories: "+strings.Join(categories, ", "))
+	}
+
+	quota := "Rate limit: unknown"
+	if s.Quota.Known {
+		quota = fmt.Sprintf("Rate limit: %d of %d requests left", s.Quota.Remaining, s.Quota.Limit)
+		if !s.Quota.Reset.IsZero() {
+			quota += fmt.Sprintf(", resets in %v", time.Until(s.Quota.Reset).Round(time.Second))
+		}
+	}
+	lines = append(lines, quota)
+	lines = append(lines, "Circuit breaker: "+s.Breaker.String())
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,132 @@
+package jokeclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		server    *httptest.Server
+		client    *jokeclient.Client
+		pingReply string
+		remaining string
+		pings     int
+		infos     int
+	)
+
+	BeforeEach(func() {
+		pingReply = `{"error":false,"ping":"Pong!","timestamp":1}`
+		remaining = "117"
+		pings = 0
+		infos = 0
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+			pings++
+			w.Header().Set("RateLimit-Limit", "120")
+			w.Header().Set("RateLimit-Remaining", remaining)
+			w.Header().Set("RateLimit-Reset", "30")
+			fmt.Fprint(w, pingReply)
+		})
+		mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+			infos++
+			fmt.Fprint(w, `{"error":false,"version":"2.3.3","jokes":{"totalCount":356,"categories":["Any","Misc","Programming"],"idRange":{"en":[0,318],"de":[0,35]},"safeJokes":[{"lang":"de","count":12},{"lang":"en","count":180}]}}`)
+		})
+
+		server = httptest.NewServer(mux)
+		client = jokeclient.NewClient(jokeclient.WithRateLimiter(jokeclient.NewRateLimiter(false)))
+		client.BaseURL = server.URL + "/joke"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should ping the API", func() {
+		latency, err := client.Ping(context.Background())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(latency).To(BeNumerically(">", 0))
+		Expect(client.Quota().Remaining).To(Equal(117))
+	})
+
+	It("should fail a ping with an unexpected reply", func() {
+		pingReply = `{"error":false}`
+
+		_, err := client.Ping(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("unexpected reply")))
+	})
+
+	It("should ping once without retrying", func() {
+		client.Retry = jokeclient.RetryPolicy{MaxAttempts: 3}
+		pingReply = `{"error":true,"code":500,"message":"Internal error"}`
+
+		_, err := client.Ping(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("ping failed")))
+		Expect(pings).To(Equal(1))
+	})
+
+	It("should ping without waiting for an exhausted quota", func() {
+		remaining = "0"
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Ping(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(pings).To(Equal(2))
+		Expect(client.Quota().Remaining).To(Equal(0))
+	})
+
+	It("should report a healthy API", func() {
+		status := client.Status(context.Background())
+
+		Expect(status.Healthy()).To(BeTrue())
+		Expect(status.Info.Version).To(Equal("2.3.3"))
+
+		report := status.Report()
+		Expect(report).To(HavePrefix("JokeAPI: healthy (ping "))
+		Expect(report).To(ContainSubstring("Jokes: 356 (de 36, en 319)"))
+		Expect(report).To(ContainSubstring("Safe jokes: de 12, en 180"))
+		Expect(report).To(ContainSubstring("Categories: Misc, Programming\n"))
+		// The info is fetched after the ping, spending one more request
+		Expect(report).To(ContainSubstring("Rate limit: 116 of 120 requests left, resets in"))
+		Expect(report).To(HaveSuffix("Circuit breaker: closed"))
+	})
+
+	It("should report an unreachable API as unhealthy", func() {
+		server.Close()
+
+		status := client.Status(context.Background())
+
+		Expect(status.Healthy()).To(BeFalse())
+		Expect(status.Info).To(BeNil())
+		Expect(status.Report()).To(HavePrefix("JokeAPI: unhealthy: ping failed"))
+		Expect(status.Report()).To(ContainSubstring("Rate limit: unknown"))
+		Expect(infos).To(BeZero())
+	})
+
+	It("should report an exhausted rate limit as unhealthy", func() {
+		remaining = "0"
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		status := client.Status(ctx)
+
+		Expect(ctx.Err()).NotTo(HaveOccurred())
+		Expect(status.Healthy()).To(BeFalse())
+		Expect(status.Info).To(BeNil())
+		Expect(infos).To(BeZero())
+		Expect(status.Report()).To(HavePrefix("JokeAPI: unhealthy: rate limit exhausted"))
+	})
+})
@@ -0,0 +1,59 @@
+package jokeclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+// SubmitJoke submits a joke to JokeAPI. With dryRun set the API checks the
+// submission without saving it. Submissions are sent once and never retried,
+// so a slow reply cannot lead to the same joke being submitted twice.
+func (c *Client) SubmitJoke(ctx context.Context, submission model.Submission, dryRun bool) (*model.SubmissionResponse, error) {
+	submission.FormatVersion = model.SubmissionFormatVersion
+	if submission.Lang == "" {
+		submission.Lang = c.Language
+	}
+	if submission.Lang == "" {
+		submission.Lang = "en"
+	}
+
+	if err := submission.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid submission: %w", err)
+	}
+	category, err := ParseCategory(string(submission.Category))
+	if err != nil {
+		return nil, fmt.Errorf("invalid submission: %w", err)
+	}
+	submission.Category = category
+	if _, ok := lookup(SupportedLanguages(), submission.Lang); !ok {
+		return nil, fmt.Errorf("invalid submission: unsupported language: %s", submission.Lang)
+	}
+
+	payload, err := json.Marshal(submission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode submission: %w", err)
+	}
+
+	url := c.apiURL("/submit")
+	if dryRun {
+		url += "?dry-run"
+	}
+	if c.Debug {
+		c.writeDebug("SUBMIT: %s %s\n", url, payload)
+	}
+
+	body, _, err := c.send(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit joke: %w", err)
+	}
+
+	var resp model.SubmissionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return &resp, nil
+}
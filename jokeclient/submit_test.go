@@ -0,0 +1,124 @@
+package jokeclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("SubmitJoke", func() {
+	var (
+		server     *httptest.Server
+		client     *jokeclient.Client
+		requests   int
+		requestURI string
+		method     string
+		received   model.Submission
+		status     int
+		reply      string
+	)
+
+	submission := model.Submission{
+		Category: "programming",
+		Type:     model.JokeTypeTwoPart,
+		Setup:    "Why do programmers prefer dark mode?",
+		Delivery: "Because light attracts bugs.",
+		Flags:    model.Flags{Explicit: true},
+	}
+
+	BeforeEach(func() {
+		requests = 0
+		status = http.StatusCreated
+		reply = `{"error":false,"message":"Joke submission was successfully saved. It will soon be checked out by the author.","timestamp":1}`
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			requestURI = r.URL.RequestURI()
+			method = r.Method
+			body, _ := io.ReadAll(r.Body)
+			received = model.Submission{}
+			json.Unmarshal(body, &received)
+
+			w.WriteHeader(status)
+			fmt.Fprint(w, reply)
+		}))
+		client = jokeclient.NewClient()
+		client.BaseURL = server.URL + "/joke"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should POST the submission to /submit", func() {
+		resp, err := client.SubmitJoke(context.Background(), submission, false)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Message).To(ContainSubstring("successfully saved"))
+		Expect(method).To(Equal(http.MethodPost))
+		Expect(requestURI).To(Equal("/submit"))
+
+		Expect(received.FormatVersion).To(Equal(model.SubmissionFormatVersion))
+		Expect(received.Category).To(Equal(model.CategoryProgramming))
+		Expect(received.Delivery).To(Equal("Because light attracts bugs."))
+		Expect(received.Flags).To(Equal(model.Flags{Explicit: true}))
+		Expect(received.Lang).To(Equal("en"))
+	})
+
+	It("should ask for a dry run", func() {
+		reply = `{"error":false,"message":"Dry Run complete! No errors were found.","timestamp":1}`
+
+		resp, err := client.SubmitJoke(context.Background(), submission, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Message).To(Equal("Dry Run complete! No errors were found."))
+		Expect(requestURI).To(Equal("/submit?dry-run"))
+	})
+
+	It("should submit in the client's language", func() {
+		client.Language = "de"
+
+		_, err := client.SubmitJoke(context.Background(), submission, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(received.Lang).To(Equal("de"))
+	})
+
+	It("should reject invalid submissions without calling the API", func() {
+		invalid := submission
+		invalid.Delivery = ""
+		_, err := client.SubmitJoke(context.Background(), invalid, false)
+		Expect(err).To(MatchError(ContainSubstring("invalid submission: the delivery is required")))
+
+		invalid = submission
+		invalid.Category = "Knock-Knock"
+		_, err = client.SubmitJoke(context.Background(), invalid, false)
+		Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeTrue())
+
+		Expect(requests).To(Equal(0))
+	})
+
+	It("should report errors from the API without retrying", func() {
+		client.Retry = jokeclient.RetryPolicy{MaxAttempts: 3}
+		status = http.StatusBadRequest
+		reply = `{"error":true,"internalError":false,"code":105,"message":"Malformed Joke","causedBy":["The joke is a duplicate"]}`
+
+		_, err := client.SubmitJoke(context.Background(), submission, false)
+
+		var apiErr *model.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.Code).To(Equal(105))
+		Expect(err).To(MatchError(ContainSubstring("failed to submit joke")))
+		Expect(requests).To(Equal(1))
+	})
+})
@@ -0,0 +1,217 @@
+// Package llm creates the language model that parses joke requests and
+// enhances jokes, from whichever provider is configured
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+
+	"github.com/AriT93/ai-agent/config"
+)
+
+// ErrDisabled is returned by New when no language model is configured
+var ErrDisabled = errors.New("no language model configured")
+
+// MistralBaseURL is Mistral's OpenAI-compatible API
+const MistralBaseURL = "https://api.mistral.ai/v1"
+
+// defaultModels are used when no model name is configured
+var defaultModels = map[string]string{
+	config.LLMOpenAI:    "gpt-3.5-turbo",
+	config.LLMOllama:    "llama3.2",
+	config.LLMAnthropic: "claude-3-5-haiku-latest",
+	config.LLMMistral:   "mistral-small-latest",
+}
+
+// keyVariables name the environment variable holding each provider's API key
+var keyVariables = map[string]string{
+	config.LLMOpenAI:    "OPENAI_API_KEY",
+	config.LLMAnthropic: "ANTHROPIC_API_KEY",
+	config.LLMMistral:   "MISTRAL_API_KEY",
+}
+
+// Model is a language model that applies the configured temperature and
+// max tokens to every call
+type Model struct {
+	llms.Model
+	Provider string // The config.LLM provider it was created for
+	Name     string // Model name
+
+	options []llms.CallOption
+	// schemaModel creates a copy of the model whose replies the provider
+	// constrains to a JSON schema, nil if the provider has no such option
+	schemaModel func(name string, schema map[string]any) (llms.Model, error)
+}
+
+// GenerateContent generates a reply with the configured options, which
+// options given here override
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	return m.Model.GenerateContent(ctx, messages, append(slices.Clone(m.options), options...)...)
+}
+
+// Call generates a reply to a single prompt with the configured options
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// WithSchema returns a model like m whose replies the provider constrains to
+// schema, or m itself if the provider has no structured output
+func (m *Model) WithSchema(name string, schema map[string]any) (*Model, error) {
+	if m.schemaModel == nil {
+		return m, nil
+	}
+
+	model, err := m.schemaModel(name, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s language model: %w", m.Provider, err)
+	}
+	constrained := *m
+	constrained.Model = model
+	return &constrained, nil
+}
+
+// String names the provider and model, e.g. "ollama llama3.2"
+func (m *Model) String() string {
+	return m.Provider + " " + m.Name
+}
+
+// Provider returns the provider cfg selects. Without one, OpenAI is used if
+// OPENAI_API_KEY is set and no model otherwise.
+func Provider(cfg config.LLM) string {
+	if cfg.Provider != "" {
+		return cfg.Provider
+	}
+	if os.Getenv(keyVariables[config.LLMOpenAI]) != "" {
+		return config.LLMOpenAI
+	}
+	return config.LLMNone
+}
+
+// New creates the language model cfg describes. It returns ErrDisabled when
+// no provider is configured.
+func New(cfg config.LLM) (*Model, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	provider := Provider(cfg)
+	name := cfg.Model
+	if name == "" {
+		name = defaultModels[provider]
+	}
+
+	key := cfg.APIKey
+	if key == "" {
+		key = os.Getenv(keyVariables[provider])
+	}
+
+	var model llms.Model
+	var schemaModel func(string, map[string]any) (llms.Model, error)
+	var err error
+	switch provider {
+	case config.LLMNone:
+		return nil, ErrDisabled
+
+	case config.LLMOpenAI, config.LLMMistral, config.LLMOpenAICompatible:
+		if name == "" {
+			return nil, fmt.Errorf("the %s LLM provider needs a model name", provider)
+		}
+		baseURL := cfg.BaseURL
+		if baseURL == "" && provider == config.LLMMistral {
+			baseURL = MistralBaseURL
+		}
+		if key == "" && provider == config.LLMOpenAICompatible {
+			// Local servers such as llama.cpp don't check the key, but the client insists on one
+			key = "none"
+		}
+		if key == "" {
+			return nil, fmt.Errorf("the %s LLM provider needs an API key: set %s or LLM_API_KEY", provider, keyVariables[provider])
+		}
+
+		options := []openai.Option{openai.WithToken(key), openai.WithModel(name)}
+		if baseURL != "" {
+			options = append(options, openai.WithBaseURL(baseURL))
+		}
+		model, err = openai.New(options...)
+
+		// The response format is a client option, so each schema needs a client
+		schemaModel = func(name string, schema map[string]any) (llms.Model, error) {
+			format, err := responseFormat(name, schema)
+			if err != nil {
+				return nil, err
+			}
+			return openai.New(append(slices.Clone(options), openai.WithResponseFormat(format))...)
+		}
+
+	case config.LLMOllama:
+		options := []ollama.Option{ollama.WithModel(name)}
+		if cfg.BaseURL != "" {
+			options = append(options, ollama.WithServerURL(cfg.BaseURL))
+		}
+		model, err = ollama.New(options...)
+
+	case config.LLMAnthropic:
+		if key == "" {
+			return nil, fmt.Errorf("the %s LLM provider needs an API key: set %s or LLM_API_KEY", provider, keyVariables[provider])
+		}
+		options := []anthropic.Option{anthropic.WithToken(key), anthropic.WithModel(name)}
+		if cfg.BaseURL != "" {
+			options = append(options, anthropic.WithBaseURL(cfg.BaseURL))
+		}
+		model, err = anthropic.New(options...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s language model: %w", provider, err)
+	}
+
+	wrapped := Wrap(model, provider, name, cfg)
+	wrapped.schemaModel = schemaModel
+	return wrapped, nil
+}
+
+// responseFormat asks an OpenAI-compatible API for replies following schema.
+// Keywords the client has no field for, such as minimum, are dropped, and
+// strict mode is off because it would make every property required.
+func responseFormat(name string, schema map[string]any) (*openai.ResponseFormat, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	var property openai.ResponseFormatJSONSchemaProperty
+	if err := json.Unmarshal(data, &property); err != nil {
+		return nil, fmt.Errorf("failed to convert schema: %w", err)
+	}
+
+	return &openai.ResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: &openai.ResponseFormatJSONSchema{Name: name, Schema: &property},
+	}, nil
+}
+
+// Wrap applies the temperature and max tokens in cfg to every call to model
+func Wrap(model llms.Model, provider, name string, cfg config.LLM) *Model {
+	var options []llms.CallOption
+	if cfg.Temperature != nil {
+		options = append(options, llms.WithTemperature(*cfg.Temperature))
+	}
+
+	maxTokens := cfg.MaxTokens
+	if maxTokens == 0 && provider == config.LLMAnthropic {
+		// Anthropic requires a limit where other providers have a default
+		maxTokens = 1024
+	}
+	if maxTokens > 0 {
+		options = append(options, llms.WithMaxTokens(maxTokens))
+	}
+
+	return &Model{Model: model, Provider: provider, Name: name, options: options}
+}
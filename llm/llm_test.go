@@ -0,0 +1,159 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/AriT93/ai-agent/config"
+	"github.com/AriT93/ai-agent/llm"
+)
+
+// recordingModel remembers the options of the last call
+type recordingModel struct {
+	options llms.CallOptions
+}
+
+func (r *recordingModel) GenerateContent(_ context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	r.options = llms.CallOptions{}
+	for _, option := range options {
+		option(&r.options)
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "ok"}}}, nil
+}
+
+func (r *recordingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+var _ = Describe("LLM", func() {
+	BeforeEach(func() {
+		for _, name := range []string{"OPENAI_API_KEY", "ANTHROPIC_API_KEY", "MISTRAL_API_KEY"} {
+			GinkgoT().Setenv(name, "")
+		}
+	})
+
+	temperature := func(t float64) *float64 { return &t }
+
+	Describe("Provider", func() {
+		It("should use the configured provider", func() {
+			Expect(llm.Provider(config.LLM{Provider: config.LLMOllama})).To(Equal(config.LLMOllama))
+		})
+
+		It("should fall back to OpenAI when its key is set", func() {
+			Expect(llm.Provider(config.LLM{})).To(Equal(config.LLMNone))
+
+			GinkgoT().Setenv("OPENAI_API_KEY", "sk-test")
+			Expect(llm.Provider(config.LLM{})).To(Equal(config.LLMOpenAI))
+		})
+	})
+
+	Describe("New", func() {
+		It("should be disabled without a provider", func() {
+			_, err := llm.New(config.LLM{})
+			Expect(errors.Is(err, llm.ErrDisabled)).To(BeTrue())
+
+			_, err = llm.New(config.LLM{Provider: config.LLMNone})
+			Expect(errors.Is(err, llm.ErrDisabled)).To(BeTrue())
+		})
+
+		It("should create an Ollama model without any key", func() {
+			model, err := llm.New(config.LLM{Provider: config.LLMOllama, BaseURL: "http://localhost:11434"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(model.String()).To(Equal("ollama llama3.2"))
+		})
+
+		DescribeTable("should require an API key for cloud providers",
+			func(provider, variable string) {
+				_, err := llm.New(config.LLM{Provider: provider})
+				Expect(err).To(MatchError(ContainSubstring("set " + variable)))
+			},
+			Entry("OpenAI", config.LLMOpenAI, "OPENAI_API_KEY"),
+			Entry("Anthropic", config.LLMAnthropic, "ANTHROPIC_API_KEY"),
+			Entry("Mistral", config.LLMMistral, "MISTRAL_API_KEY"),
+		)
+
+		It("should accept the provider's own key variable", func() {
+			GinkgoT().Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+
+			model, err := llm.New(config.LLM{Provider: config.LLMAnthropic, Model: "claude-3-5-sonnet-latest"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(model.String()).To(Equal("anthropic claude-3-5-sonnet-latest"))
+		})
+
+		It("should reject invalid settings", func() {
+			_, err := llm.New(config.LLM{Provider: config.LLMOpenAICompatible})
+			Expect(err).To(MatchError(ContainSubstring("needs a base URL")))
+		})
+
+		It("should talk to an OpenAI-compatible server with the configured settings", func() {
+			var request map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/v1/chat/completions"))
+				Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"id":"1","object":"chat.completion","created":1,"model":"qwen2.5","choices":[{"index":0,"message":{"role":"assistant","content":"category=pun"},"finish_reason":"stop"}]}`)
+			}))
+			DeferCleanup(server.Close)
+
+			model, err := llm.New(config.LLM{
+				Provider:    config.LLMOpenAICompatible,
+				Model:       "qwen2.5",
+				BaseURL:     server.URL + "/v1",
+				Temperature: temperature(0.1),
+				MaxTokens:   64,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			reply, err := llms.GenerateFromSinglePrompt(context.Background(), model, "a pun please")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reply).To(Equal("category=pun"))
+			Expect(request["model"]).To(Equal("qwen2.5"))
+			Expect(request["temperature"]).To(BeNumerically("~", 0.1))
+			Expect(request).To(Or(HaveKeyWithValue("max_tokens", BeNumerically("==", 64)), HaveKeyWithValue("max_completion_tokens", BeNumerically("==", 64))))
+		})
+	})
+
+	Describe("Wrap", func() {
+		It("should apply the configured options to every call", func() {
+			recorder := &recordingModel{}
+			model := llm.Wrap(recorder, config.LLMOllama, "llama3.2", config.LLM{Temperature: temperature(0.3), MaxTokens: 100})
+
+			_, err := model.Call(context.Background(), "hello")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.options.Temperature).To(Equal(0.3))
+			Expect(recorder.options.MaxTokens).To(Equal(100))
+		})
+
+		It("should let options given with the call win", func() {
+			recorder := &recordingModel{}
+			model := llm.Wrap(recorder, config.LLMOllama, "llama3.2", config.LLM{Temperature: temperature(0.3)})
+
+			_, err := model.Call(context.Background(), "hello", llms.WithTemperature(0.9))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.options.Temperature).To(Equal(0.9))
+		})
+
+		It("should give Anthropic a max tokens limit", func() {
+			recorder := &recordingModel{}
+			model := llm.Wrap(recorder, config.LLMAnthropic, "claude-3-5-haiku-latest", config.LLM{})
+
+			_, err := model.Call(context.Background(), "hello")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.options.MaxTokens).To(Equal(1024))
+		})
+	})
+})
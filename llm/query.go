@@ -0,0 +1,237 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+// ErrInvalidOutput is returned when the model's reply is not a valid query,
+// even after it was asked to repair it
+var ErrInvalidOutput = errors.New("invalid query from language model")
+
+// parsePrompt asks for the query as JSON. It is filled in with the schema
+// and the user's request.
+const parsePrompt = `Turn this request for a joke into a JSON object that follows the JSON schema below.
+Only include properties that are stated or implied in the request. If the user specifically asks for NSFW jokes, do not blacklist nsfw.
+Reply with the JSON object only, without explanation or code fences.
+
+JSON schema:
+%s
+
+Request: %s`
+
+// repairPrompt asks the model to fix a reply that was rejected
+const repairPrompt = `That reply is not valid: %v
+Reply with a corrected JSON object that follows the schema, and nothing else.`
+
+// queryOutput is the JSON object the model replies with
+type queryOutput struct {
+	Categories []string `json:"categories"`
+	Type       string   `json:"type"`
+	Blacklist  []string `json:"blacklist"`
+	Amount     int      `json:"amount"`
+	Contains   string   `json:"contains"`
+	IDRange    string   `json:"idRange"`
+	Language   string   `json:"lang"`
+	SafeMode   bool     `json:"safeMode"`
+}
+
+// QuerySchema returns the JSON schema the model's reply must follow, with
+// the categories, flags and languages currently known to jokeclient
+func QuerySchema() map[string]any {
+	enum := func(values []string) map[string]any {
+		return map[string]any{"type": "string", "enum": values}
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"categories": map[string]any{
+				"type":        "array",
+				"items":       enum(jokeclient.KnownCategories()),
+				"description": "Joke categories, any category if empty",
+			},
+			"type": map[string]any{
+				"type":        "string",
+				"enum":        []string{jokeclient.TypeSingle, jokeclient.TypeTwoPart},
+				"description": "single for one-liners, twopart for a setup and delivery",
+			},
+			"blacklist": map[string]any{
+				"type":        "array",
+				"items":       enum(jokeclient.KnownFlags()),
+				"description": "Kinds of content the joke must not contain",
+			},
+			"amount": map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"maximum":     jokeclient.MaxAmount,
+				"description": "Number of jokes requested",
+			},
+			"contains": map[string]any{
+				"type":        "string",
+				"description": `A single keyword the joke should mention if the user asks about a topic that is not a category, e.g. "about coffee" is coffee`,
+			},
+			"idRange": map[string]any{
+				"type":        "string",
+				"pattern":     "^[0-9]+(-[0-9]+)?$",
+				"description": "A joke ID or range of IDs such as 10-50 if the user asks for specific joke numbers",
+			},
+			"lang": map[string]any{
+				"type":        "string",
+				"enum":        jokeclient.SupportedLanguages(),
+				"description": `Language code if the user asks for jokes in a language, e.g. "in German" is de`,
+			},
+			"safeMode": map[string]any{
+				"type":        "boolean",
+				"description": "True if the user wants jokes suitable for everyone",
+			},
+		},
+	}
+}
+
+// QueryParser turns free text requests into joke queries with a language
+// model asked to follow QuerySchema. Providers with structured output are
+// given the schema as the response format, so their replies are constrained
+// to it; the others only see it in the prompt.
+type QueryParser struct {
+	Model llms.Model
+	Log   func(format string, args ...interface{}) // Debug log for replies, nil to disable
+}
+
+// NewQueryParser creates a parser that asks model
+func NewQueryParser(model llms.Model) *QueryParser {
+	return &QueryParser{Model: model}
+}
+
+// Parse asks the model for the query described by input. A reply that is
+// not valid JSON, does not follow the schema or fails validation is sent
+// back once with the reason so the model can repair it.
+func (p *QueryParser) Parse(ctx context.Context, input string) (jokeclient.JokeQuery, error) {
+	querySchema := QuerySchema()
+	schema, err := json.MarshalIndent(querySchema, "", "  ")
+	if err != nil {
+		return jokeclient.JokeQuery{}, fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	model := p.Model
+	if configured, ok := model.(*Model); ok {
+		if model, err = configured.WithSchema("joke_query", querySchema); err != nil {
+			return jokeclient.JokeQuery{}, err
+		}
+	}
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(parsePrompt, schema, input)),
+	}
+
+	reply, err := p.generate(ctx, model, messages)
+	if err != nil {
+		return jokeclient.JokeQuery{}, err
+	}
+
+	query, err := DecodeQuery(reply)
+	if err == nil {
+		return query, nil
+	}
+	p.log("LLM REPLY REJECTED: %v\n", err)
+
+	// Show the model its reply and what was wrong with it, and try once more
+	messages = append(messages,
+		llms.TextParts(llms.ChatMessageTypeAI, reply),
+		llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(repairPrompt, err)),
+	)
+	reply, err = p.generate(ctx, model, messages)
+	if err != nil {
+		return jokeclient.JokeQuery{}, err
+	}
+
+	query, err = DecodeQuery(reply)
+	if err != nil {
+		p.log("LLM REPAIR REJECTED: %v\n", err)
+		return jokeclient.JokeQuery{}, fmt.Errorf("after one repair: %w", err)
+	}
+	return query, nil
+}
+
+// generate sends the conversation to model in JSON mode and returns the reply
+func (p *QueryParser) generate(ctx context.Context, model llms.Model, messages []llms.MessageContent) (string, error) {
+	resp, err := model.GenerateContent(ctx, messages, llms.WithJSONMode())
+	if err != nil {
+		return "", fmt.Errorf("language model failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("language model failed: empty response")
+	}
+
+	reply := resp.Choices[0].Content
+	p.log("LLM REPLY: %s\n", reply)
+	return reply, nil
+}
+
+// log writes to the debug log, if there is one
+func (p *QueryParser) log(format string, args ...interface{}) {
+	if p.Log != nil {
+		p.Log(format, args...)
+	}
+}
+
+// DecodeQuery parses a reply following QuerySchema into a validated query.
+// Errors wrap ErrInvalidOutput.
+func DecodeQuery(reply string) (jokeclient.JokeQuery, error) {
+	decoder := json.NewDecoder(strings.NewReader(stripCodeFence(reply)))
+	decoder.DisallowUnknownFields()
+
+	var output queryOutput
+	if err := decoder.Decode(&output); err != nil {
+		return jokeclient.JokeQuery{}, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	if decoder.More() {
+		return jokeclient.JokeQuery{}, fmt.Errorf("%w: unexpected text after the JSON object", ErrInvalidOutput)
+	}
+
+	query := jokeclient.JokeQuery{
+		Type:           output.Type,
+		BlacklistFlags: output.Blacklist,
+		Amount:         output.Amount,
+		Contains:       output.Contains,
+		ContainsGuess:  output.Contains != "",
+		Language:       output.Language,
+		SafeMode:       output.SafeMode,
+	}
+	for _, category := range output.Categories {
+		if !strings.EqualFold(category, "any") {
+			query.Categories = append(query.Categories, category)
+		}
+	}
+	if output.IDRange != "" {
+		idRange, err := jokeclient.ParseIDRange(output.IDRange)
+		if err != nil {
+			return jokeclient.JokeQuery{}, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+		}
+		query.IDRange = idRange
+	}
+
+	if err := query.Validate(); err != nil {
+		return jokeclient.JokeQuery{}, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	return query.Normalize(), nil
+}
+
+// stripCodeFence removes the Markdown code fence models sometimes wrap JSON in
+func stripCodeFence(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if !strings.HasPrefix(reply, "```") {
+		return reply
+	}
+	reply = strings.TrimPrefix(reply, "```")
+	reply = strings.TrimPrefix(reply, "json")
+	return strings.TrimSpace(strings.TrimSuffix(reply, "```"))
+}
@@ -0,0 +1,242 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+
+	"github.com/AriT93/ai-agent/config"
+	"github.com/AriT93/ai-agent/jokeclient"
+	"github.com/AriT93/ai-agent/llm"
+)
+
+// scriptedModel replies with the given replies in turn and records each
+// conversation it was sent and the options it was sent with
+type scriptedModel struct {
+	replies       []string
+	conversations [][]llms.MessageContent
+	options       []llms.CallOptions
+	err           error
+}
+
+func (s *scriptedModel) GenerateContent(_ context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	s.conversations = append(s.conversations, messages)
+	s.options = append(s.options, opts)
+
+	if s.err != nil {
+		return nil, s.err
+	}
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: reply}}}, nil
+}
+
+func (s *scriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, s, prompt, options...)
+}
+
+// text returns the text of a message
+func text(message llms.MessageContent) string {
+	return message.Parts[0].(llms.TextContent).Text
+}
+
+var _ = Describe("QueryParser", func() {
+	It("should parse a valid reply into a query", func() {
+		parser := llm.NewQueryParser(fake.NewFakeLLM([]string{
+			`{"categories": ["programming", "pun"], "type": "twopart", "blacklist": ["nsfw", "political"], "lang": "de"}`,
+		}))
+
+		query, err := parser.Parse(context.Background(), "a clean two-part programming or pun joke in German")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query).To(Equal(jokeclient.JokeQuery{
+			Categories:     []string{"Programming", "Pun"},
+			Type:           jokeclient.TypeTwoPart,
+			BlacklistFlags: []string{"nsfw", "political"},
+			Language:       "de",
+		}))
+	})
+
+	It("should parse every property of the schema", func() {
+		parser := llm.NewQueryParser(fake.NewFakeLLM([]string{
+			`{"amount": 3, "contains": "coffee", "idRange": "10-50", "safeMode": true}`,
+		}))
+
+		query, err := parser.Parse(context.Background(), "three safe jokes about coffee between 10 and 50")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Amount).To(Equal(3))
+		Expect(query.Contains).To(Equal("coffee"))
+		Expect(query.ContainsGuess).To(BeTrue())
+		Expect(query.IDRange).To(Equal(&jokeclient.IDRange{From: 10, To: 50}))
+		Expect(query.SafeMode).To(BeTrue())
+	})
+
+	It("should ask for JSON following the schema", func() {
+		model := &scriptedModel{replies: []string{`{}`}}
+		parser := llm.NewQueryParser(model)
+
+		_, err := parser.Parse(context.Background(), "tell me a joke")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(model.options[0].JSONMode).To(BeTrue())
+		prompt := text(model.conversations[0][0])
+		Expect(prompt).To(ContainSubstring(`"additionalProperties": false`))
+		Expect(prompt).To(ContainSubstring(`"Christmas"`))
+		Expect(prompt).To(HaveSuffix("Request: tell me a joke"))
+	})
+
+	It("should give an OpenAI-compatible server the schema as the response format", func() {
+		var request struct {
+			ResponseFormat struct {
+				Type       string `json:"type"`
+				JSONSchema struct {
+					Name   string         `json:"name"`
+					Schema map[string]any `json:"schema"`
+				} `json:"json_schema"`
+			} `json:"response_format"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id":"1","object":"chat.completion","created":1,"model":"qwen2.5","choices":[{"index":0,"message":{"role":"assistant","content":"{\"categories\":[\"Pun\"]}"},"finish_reason":"stop"}]}`)
+		}))
+		DeferCleanup(server.Close)
+
+		model, err := llm.New(config.LLM{Provider: config.LLMOpenAICompatible, Model: "qwen2.5", BaseURL: server.URL + "/v1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		query, err := llm.NewQueryParser(model).Parse(context.Background(), "a pun please")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Categories).To(Equal([]string{"Pun"}))
+		Expect(request.ResponseFormat.Type).To(Equal("json_schema"))
+		Expect(request.ResponseFormat.JSONSchema.Name).To(Equal("joke_query"))
+		Expect(request.ResponseFormat.JSONSchema.Schema).To(HaveKeyWithValue("properties", HaveKey("categories")))
+	})
+
+	It("should leave models without structured output as they are", func() {
+		model := llm.Wrap(&scriptedModel{}, config.LLMOllama, "llama3.2", config.LLM{})
+
+		constrained, err := model.WithSchema("joke_query", llm.QuerySchema())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(constrained).To(BeIdenticalTo(model))
+	})
+
+	It("should repair a malformed reply once", func() {
+		model := &scriptedModel{replies: []string{
+			`category=programming&type=single`,
+			`{"categories": ["programming"], "type": "single"}`,
+		}}
+		var logged []string
+		parser := llm.NewQueryParser(model)
+		parser.Log = func(format string, args ...interface{}) { logged = append(logged, format) }
+
+		query, err := parser.Parse(context.Background(), "a programming one-liner")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Categories).To(Equal([]string{"Programming"}))
+		Expect(query.Type).To(Equal(jokeclient.TypeSingle))
+
+		Expect(model.conversations).To(HaveLen(2))
+		repair := model.conversations[1]
+		Expect(repair).To(HaveLen(3))
+		Expect(repair[1].Role).To(Equal(llms.ChatMessageTypeAI))
+		Expect(text(repair[1])).To(Equal("category=programming&type=single"))
+		Expect(text(repair[2])).To(ContainSubstring("That reply is not valid: invalid query from language model"))
+		Expect(model.options[1].JSONMode).To(BeTrue())
+		Expect(logged).To(ContainElement("LLM REPLY REJECTED: %v\n"))
+	})
+
+	It("should repair a reply that fails validation", func() {
+		model := &scriptedModel{replies: []string{
+			`{"categories": ["knock-knock"]}`,
+			`{"categories": ["misc"]}`,
+		}}
+
+		query, err := llm.NewQueryParser(model).Parse(context.Background(), "a knock-knock joke")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Categories).To(Equal([]string{"Misc"}))
+		Expect(text(model.conversations[1][2])).To(ContainSubstring("invalid joke category: knock-knock"))
+	})
+
+	It("should give up when the repair is invalid too", func() {
+		model := &scriptedModel{replies: []string{`not json`, `{"type": "threepart"}`}}
+
+		_, err := llm.NewQueryParser(model).Parse(context.Background(), "a joke")
+
+		Expect(errors.Is(err, llm.ErrInvalidOutput)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("after one repair")))
+		Expect(model.conversations).To(HaveLen(2))
+	})
+
+	It("should report model failures without a repair", func() {
+		model := &scriptedModel{err: errors.New("connection refused")}
+
+		_, err := llm.NewQueryParser(model).Parse(context.Background(), "a joke")
+
+		Expect(err).To(MatchError("language model failed: connection refused"))
+		Expect(model.conversations).To(HaveLen(1))
+	})
+
+	Describe("DecodeQuery", func() {
+		It("should accept JSON in a code fence", func() {
+			query, err := llm.DecodeQuery("```json\n{\"type\": \"single\"}\n```")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.Type).To(Equal(jokeclient.TypeSingle))
+		})
+
+		It("should ignore the Any category", func() {
+			query, err := llm.DecodeQuery(`{"categories": ["Any"]}`)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.Categories).To(BeEmpty())
+		})
+
+		DescribeTable("should reject replies that break the schema",
+			func(reply, message string) {
+				_, err := llm.DecodeQuery(reply)
+
+				Expect(errors.Is(err, llm.ErrInvalidOutput)).To(BeTrue())
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("not JSON", `programming please`, "invalid character"),
+			Entry("unknown property", `{"category": "pun"}`, `unknown field "category"`),
+			Entry("wrong property type", `{"amount": "three"}`, "cannot unmarshal"),
+			Entry("trailing text", `{"type": "single"} Hope that helps!`, "unexpected text"),
+			Entry("unknown joke type", `{"type": "threepart"}`, "invalid joke type"),
+			Entry("unknown flag", `{"blacklist": ["boring"]}`, "invalid blacklist flag"),
+			Entry("unsupported language", `{"lang": "xx"}`, "unsupported language"),
+			Entry("too many jokes", `{"amount": 11}`, "amount must be between"),
+			Entry("malformed ID range", `{"idRange": "50-10"}`, "invalid ID range"),
+		)
+	})
+
+	It("should describe every property in the schema", func() {
+		schema, err := json.Marshal(llm.QuerySchema())
+		Expect(err).NotTo(HaveOccurred())
+
+		var decoded struct {
+			Properties map[string]any `json:"properties"`
+		}
+		Expect(json.Unmarshal(schema, &decoded)).To(Succeed())
+		Expect(decoded.Properties).To(HaveLen(8))
+		Expect(decoded.Properties).To(HaveKey("categories"))
+		Expect(decoded.Properties).To(HaveKey("safeMode"))
+	})
+})
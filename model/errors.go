@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors that an APIError can be matched against with errors.Is
+var (
+	ErrNoMatch         = errors.New("no matching joke found")
+	ErrInvalidCategory = errors.New("invalid joke category")
+	ErrRateLimited     = errors.New("rate limited by the joke API")
+)
+
+// JokeAPI error codes
+const (
+	CodeRateLimited = 101
+	CodeNoMatch     = 106
+)
+
+// causeInvalidCategory starts the cause JokeAPI gives, with CodeNoMatch, for
+// a category it does not know
+const causeInvalidCategory = "The specified category is invalid"
+
+// APIError struct to hold the error payload returned by the API when its
+// "error" field is true
+type APIError struct {
+	InternalError  bool     `json:"internalError"`
+	Code           int      `json:"code"`
+	Message        string   `json:"message"`
+	CausedBy       []string `json:"causedBy"`
+	AdditionalInfo string   `json:"additionalInfo"`
+	Timestamp      int64    `json:"timestamp"`
+	StatusCode     int      `json:"-"` // HTTP status the payload arrived with
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("joke API error %d: %s", e.Code, e.Message)
+	if len(e.CausedBy) > 0 {
+		msg += " (" + strings.Join(e.CausedBy, " ") + ")"
+	}
+	return msg
+}
+
+// Is reports whether the API error corresponds to one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNoMatch:
+		return e.Code == CodeNoMatch
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.Code == CodeRateLimited
+	case ErrInvalidCategory:
+		return e.Code == CodeNoMatch && e.causedBy(causeInvalidCategory)
+	}
+	return false
+}
+
+// causedBy reports whether one of the causes the API listed starts with cause
+func (e *APIError) causedBy(cause string) bool {
+	for _, reason := range e.CausedBy {
+		if len(reason) >= len(cause) && strings.EqualFold(reason[:len(cause)], cause) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package model_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("APIError", func() {
+	It("should unmarshal an API error payload correctly", func() {
+		jsonData := []byte(`{
+			"error": true,
+			"internalError": false,
+			"code": 106,
+			"message": "No matching joke found",
+			"causedBy": ["No jokes were found that match your provided filter(s)."],
+			"additionalInfo": "Error while finalizing joke filtering: No jokes were found that match your provided filter(s).",
+			"timestamp": 1579170794412
+		}`)
+
+		var apiErr model.APIError
+		err := json.Unmarshal(jsonData, &apiErr)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apiErr.Code).To(Equal(106))
+		Expect(apiErr.Message).To(Equal("No matching joke found"))
+		Expect(apiErr.CausedBy).To(ConsistOf("No jokes were found that match your provided filter(s)."))
+		Expect(apiErr.AdditionalInfo).To(ContainSubstring("finalizing joke filtering"))
+		Expect(apiErr.Error()).To(ContainSubstring("No matching joke found"))
+	})
+
+	It("should match the no match sentinel by code", func() {
+		var err error = &model.APIError{Code: model.CodeNoMatch, Message: "No matching joke found"}
+		Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+		Expect(errors.Is(err, model.ErrRateLimited)).To(BeFalse())
+	})
+
+	It("should match the rate limited sentinel by status code", func() {
+		var err error = &model.APIError{StatusCode: http.StatusTooManyRequests}
+		Expect(errors.Is(err, model.ErrRateLimited)).To(BeTrue())
+	})
+
+	It("should match the invalid category sentinel by its cause", func() {
+		var err error = &model.APIError{
+			Code:     106,
+			Message:  "No matching joke found",
+			CausedBy: []string{`The specified category is invalid - Got: "foo" - Possible categories are: "Any, Misc, Programming"`},
+		}
+		Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeTrue())
+	})
+
+	It("should not match the invalid category sentinel on other errors mentioning categories", func() {
+		var err error = &model.APIError{
+			Code:     106,
+			Message:  "No matching joke found",
+			CausedBy: []string{"No jokes were found in the category Programming that match your provided filter(s)."},
+		}
+		Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeFalse())
+		Expect(errors.Is(err, model.ErrNoMatch)).To(BeTrue())
+
+		err = &model.APIError{Code: 105, Message: "Invalid category", CausedBy: []string{"The specified category is invalid"}}
+		Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeFalse())
+	})
+})
@@ -1,6 +1,7 @@
 package model
 
-// JokeResponse struct to hold the API response
+// JokeResponse struct to hold the API response. It mirrors JokeAPI's wire
+// format; convert it with JokeFromResponse for use elsewhere.
 type JokeResponse struct {
 	Error    bool   `json:"error"`
 	Category string `json:"category"`
@@ -8,15 +9,110 @@ type JokeResponse struct {
 	Setup    string `json:"setup"`
 	Delivery string `json:"delivery"`
 	Joke     string `json:"joke"`
-	Flags    struct {
-		Nsfw      bool `json:"nsfw"`
-		Religious bool `json:"religious"`
-		Political bool `json:"political"`
-		Racist    bool `json:"racist"`
-		Sexist    bool `json:"sexist"`
-		Explicit  bool `json:"explicit"`
-	} `json:"flags"`
-	ID   int    `json:"id"`
-	Safe bool   `json:"safe"`
-	Lang string `json:"lang"`
+	Flags    Flags  `json:"flags"`
+	ID       int    `json:"id"`
+	Safe     bool   `json:"safe"`
+	Lang     string `json:"lang"`
+	Provider string `json:"-"` // Name of the provider that served the joke
+}
+
+// JokesResponse struct to hold the API response when more than one joke is requested
+type JokesResponse struct {
+	Error  bool           `json:"error"`
+	Amount int            `json:"amount"`
+	Jokes  []JokeResponse `json:"jokes"`
+}
+
+// Joke is a joke with its metadata, independent of the API that served it
+type Joke struct {
+	ID       int      `json:"id"`
+	Category Category `json:"category"`
+	Type     JokeType `json:"type"`
+	Body     string   `json:"body"`               // The one-liner, or the setup of a two-part joke
+	Delivery string   `json:"delivery,omitempty"` // The punchline of a two-part joke
+	Flags    Flags    `json:"flags"`
+	Safe     bool     `json:"safe"`
+	Lang     string   `json:"lang"`
+	Provider string   `json:"provider,omitempty"`
+}
+
+// JokeFromResponse converts an API response into a Joke
+func JokeFromResponse(r JokeResponse) Joke {
+	joke := Joke{
+		ID:       r.ID,
+		Category: Category(r.Category),
+		Type:     JokeType(r.Type),
+		Body:     r.Joke,
+		Flags:    r.Flags,
+		Safe:     r.Safe,
+		Lang:     r.Lang,
+		Provider: r.Provider,
+	}
+
+	if joke.IsTwoPart() {
+		joke.Body = r.Setup
+		joke.Delivery = r.Delivery
+	}
+
+	return joke
+}
+
+// JokesFromResponse converts a batch response into Jokes
+func JokesFromResponse(r JokesResponse) []Joke {
+	jokes := make([]Joke, len(r.Jokes))
+	for i, response := range r.Jokes {
+		jokes[i] = JokeFromResponse(response)
+	}
+	return jokes
+}
+
+// Response converts the joke back into JokeAPI's wire format
+func (j Joke) Response() JokeResponse {
+	response := JokeResponse{
+		ID:       j.ID,
+		Category: string(j.Category),
+		Type:     string(j.Type),
+		Joke:     j.Body,
+		Flags:    j.Flags,
+		Safe:     j.Safe,
+		Lang:     j.Lang,
+		Provider: j.Provider,
+	}
+
+	if j.IsTwoPart() {
+		response.Joke = ""
+		response.Setup = j.Body
+		response.Delivery = j.Delivery
+	}
+
+	return response
+}
+
+// IsTwoPart reports whether the joke has a setup and a punchline
+func (j Joke) IsTwoPart() bool {
+	return j.Type == JokeTypeTwoPart
+}
+
+// Setup returns the setup of a two-part joke, or "" for a one-liner
+func (j Joke) Setup() string {
+	if !j.IsTwoPart() {
+		return ""
+	}
+	return j.Body
+}
+
+// Punchline returns the punchline of a two-part joke, or "" for a one-liner
+func (j Joke) Punchline() string {
+	if !j.IsTwoPart() {
+		return ""
+	}
+	return j.Delivery
+}
+
+// Text returns the whole joke, with a blank line between setup and punchline
+func (j Joke) Text() string {
+	if j.IsTwoPart() {
+		return j.Body + "\n\n" + j.Delivery
+	}
+	return j.Body
 }
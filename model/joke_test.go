@@ -83,4 +83,155 @@ var _ = Describe("Joke Model", func() {
 			Expect(joke.Lang).To(Equal("en"))
 		})
 	})
+
+	Describe("JokesResponse", func() {
+		It("should unmarshal a batch of jokes correctly", func() {
+			jsonData := []byte(`{
+				"error": false,
+				"amount": 2,
+				"jokes": [
+					{
+						"category": "Pun",
+						"type": "single",
+						"joke": "I'm reading a book about anti-gravity. It's impossible to put down.",
+						"flags": {"nsfw": false},
+						"id": 10,
+						"safe": true,
+						"lang": "en"
+					},
+					{
+						"category": "Pun",
+						"type": "twopart",
+						"setup": "What do you call a fake noodle?",
+						"delivery": "An impasta.",
+						"flags": {"nsfw": false},
+						"id": 11,
+						"safe": true,
+						"lang": "en"
+					}
+				]
+			}`)
+
+			var batch model.JokesResponse
+			err := json.Unmarshal(jsonData, &batch)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.Error).To(BeFalse())
+			Expect(batch.Amount).To(Equal(2))
+			Expect(batch.Jokes).To(HaveLen(2))
+			Expect(batch.Jokes[0].Joke).To(ContainSubstring("anti-gravity"))
+			Expect(batch.Jokes[1].Type).To(Equal("twopart"))
+			Expect(batch.Jokes[1].Delivery).To(Equal("An impasta."))
+		})
+	})
+
+	Describe("Joke", func() {
+		var twoPart model.JokeResponse
+
+		BeforeEach(func() {
+			twoPart = model.JokeResponse{
+				ID:       42,
+				Category: "Programming",
+				Type:     "twopart",
+				Setup:    "Why do Java developers wear glasses?",
+				Delivery: "Because they don't C#.",
+				Safe:     true,
+				Lang:     "en",
+				Provider: "JokeAPI",
+			}
+			twoPart.Flags.Political = true
+			twoPart.Flags.Explicit = true
+		})
+
+		It("should keep the metadata of the response", func() {
+			joke := model.JokeFromResponse(twoPart)
+
+			Expect(joke.ID).To(Equal(42))
+			Expect(joke.Category).To(Equal(model.CategoryProgramming))
+			Expect(joke.Type).To(Equal(model.JokeTypeTwoPart))
+			Expect(joke.Flags.Names()).To(Equal([]string{"political", "explicit"}))
+			Expect(joke.Safe).To(BeTrue())
+			Expect(joke.Lang).To(Equal("en"))
+			Expect(joke.Provider).To(Equal("JokeAPI"))
+		})
+
+		It("should split a two-part joke into setup and punchline", func() {
+			joke := model.JokeFromResponse(twoPart)
+
+			Expect(joke.IsTwoPart()).To(BeTrue())
+			Expect(joke.Setup()).To(Equal("Why do Java developers wear glasses?"))
+			Expect(joke.Punchline()).To(Equal("Because they don't C#."))
+			Expect(joke.Text()).To(Equal("Why do Java developers wear glasses?\n\nBecause they don't C#."))
+		})
+
+		It("should give a one-liner as its text only", func() {
+			joke := model.JokeFromResponse(model.JokeResponse{Type: "single", Joke: "I'm reading a book on anti-gravity. It's impossible to put down."})
+
+			Expect(joke.IsTwoPart()).To(BeFalse())
+			Expect(joke.Setup()).To(BeEmpty())
+			Expect(joke.Punchline()).To(BeEmpty())
+			Expect(joke.Text()).To(Equal("I'm reading a book on anti-gravity. It's impossible to put down."))
+			Expect(joke.Flags.Empty()).To(BeTrue())
+		})
+	})
+
+	Describe("JSON round trips", func() {
+		It("should survive wire JSON to Joke and back", func() {
+			wire := []byte(`{
+				"error": false,
+				"category": "Pun",
+				"type": "twopart",
+				"setup": "What do you call a fake noodle?",
+				"delivery": "An impasta.",
+				"joke": "",
+				"flags": {"nsfw": false, "religious": false, "political": true, "racist": false, "sexist": false, "explicit": false},
+				"id": 7,
+				"safe": false,
+				"lang": "en"
+			}`)
+
+			var response model.JokeResponse
+			Expect(json.Unmarshal(wire, &response)).To(Succeed())
+
+			joke := model.JokeFromResponse(response)
+			Expect(joke.Flags.Has(model.FlagPolitical)).To(BeTrue())
+
+			encoded, err := json.Marshal(joke.Response())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoded).To(MatchJSON(wire))
+		})
+
+		It("should survive Joke to JSON and back", func() {
+			flags, err := model.ParseFlags("nsfw", "explicit")
+			Expect(err).NotTo(HaveOccurred())
+			jokes := []model.Joke{
+				{ID: 1, Category: model.CategoryProgramming, Type: model.JokeTypeSingle, Body: "There are 10 kinds of people.", Safe: true, Lang: "en", Provider: "JokeAPI"},
+				{ID: 2, Category: model.CategoryDark, Type: model.JokeTypeTwoPart, Body: "Setup", Delivery: "Punchline", Flags: flags, Lang: "de", Provider: "Local corpus"},
+			}
+
+			for _, joke := range jokes {
+				encoded, err := json.Marshal(joke)
+				Expect(err).NotTo(HaveOccurred())
+
+				var decoded model.Joke
+				Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+				Expect(decoded).To(Equal(joke))
+
+				Expect(model.JokeFromResponse(joke.Response())).To(Equal(joke))
+			}
+		})
+
+		It("should convert a batch of jokes", func() {
+			batch := model.JokesResponse{Amount: 2, Jokes: []model.JokeResponse{
+				{ID: 1, Category: "Misc", Type: "single", Joke: "One"},
+				{ID: 2, Category: "Pun", Type: "twopart", Setup: "Two", Delivery: "Three"},
+			}}
+
+			jokes := model.JokesFromResponse(batch)
+
+			Expect(jokes).To(HaveLen(2))
+			Expect(jokes[0].Text()).To(Equal("One"))
+			Expect(jokes[1].Punchline()).To(Equal("Three"))
+		})
+	})
 })
@@ -0,0 +1,64 @@
+package model
+
+// CategoriesResponse is JokeAPI's reply to /categories
+type CategoriesResponse struct {
+	Error           bool            `json:"error"`
+	Categories      []string        `json:"categories"`
+	CategoryAliases []CategoryAlias `json:"categoryAliases"`
+	Timestamp       int64           `json:"timestamp"`
+}
+
+// CategoryAlias is another name JokeAPI accepts for a category
+type CategoryAlias struct {
+	Alias    string `json:"alias"`
+	Resolved string `json:"resolved"`
+}
+
+// FlagsResponse is JokeAPI's reply to /flags
+type FlagsResponse struct {
+	Error     bool     `json:"error"`
+	Flags     []string `json:"flags"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// LangCodeResponse is JokeAPI's reply to /langcode/{language}
+type LangCodeResponse struct {
+	Error bool   `json:"error"`
+	Code  string `json:"code"`
+}
+
+// InfoResponse is JokeAPI's reply to /info
+type InfoResponse struct {
+	Error           bool      `json:"error"`
+	Version         string    `json:"version"`
+	Jokes           JokesInfo `json:"jokes"`
+	Formats         []string  `json:"formats"`
+	JokeLanguages   int       `json:"jokeLanguages"`
+	SystemLanguages int       `json:"systemLanguages"`
+	Info            string    `json:"info"`
+	Timestamp       int64     `json:"timestamp"`
+}
+
+// JokesInfo describes the jokes JokeAPI serves
+type JokesInfo struct {
+	TotalCount    int               `json:"totalCount"`
+	Categories    []string          `json:"categories"`
+	Flags         []string          `json:"flags"`
+	Types         []string          `json:"types"`
+	SubmissionURL string            `json:"submissionURL"`
+	IDRange       map[string][2]int `json:"idRange"`   // First and last joke ID per language code
+	SafeJokes     []LanguageCount   `json:"safeJokes"` // Number of safe jokes per language
+}
+
+// LanguageCount is a number of jokes in one language
+type LanguageCount struct {
+	Lang  string `json:"lang"`
+	Count int    `json:"count"`
+}
+
+// PingResponse is JokeAPI's reply to /ping
+type PingResponse struct {
+	Error     bool   `json:"error"`
+	Ping      string `json:"ping"`
+	Timestamp int64  `json:"timestamp"`
+}
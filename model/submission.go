@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SubmissionFormatVersion is the submission format JokeAPI expects
+const SubmissionFormatVersion = 3
+
+// MaxSubmissionLength is the longest joke text accepted for submission
+const MaxSubmissionLength = 1000
+
+// Submission is a joke to submit to JokeAPI, in its wire format
+type Submission struct {
+	FormatVersion int      `json:"formatVersion"`
+	Category      Category `json:"category"`
+	Type          JokeType `json:"type"`
+	Joke          string   `json:"joke,omitempty"`     // Only for single jokes
+	Setup         string   `json:"setup,omitempty"`    // Only for two-part jokes
+	Delivery      string   `json:"delivery,omitempty"` // Only for two-part jokes
+	Flags         Flags    `json:"flags"`
+	Lang          string   `json:"lang"`
+}
+
+// SubmissionResponse is JokeAPI's reply to a submission
+type SubmissionResponse struct {
+	Error      bool        `json:"error"`
+	Message    string      `json:"message"`
+	Submission *Submission `json:"submission"`
+	Timestamp  int64       `json:"timestamp"`
+}
+
+// Validate checks that the submission is complete and has the fields its
+// type requires. Whether the category exists depends on the categories
+// JokeAPI currently has, which the client checks before sending.
+func (s Submission) Validate() error {
+	category := strings.TrimSpace(string(s.Category))
+	if category == "" || strings.EqualFold(category, "any") {
+		return errors.New("a category is required")
+	}
+
+	switch s.Type {
+	case JokeTypeSingle:
+		if err := checkText("joke", s.Joke); err != nil {
+			return err
+		}
+		if s.Setup != "" || s.Delivery != "" {
+			return errors.New("a single joke has no setup or delivery")
+		}
+	case JokeTypeTwoPart:
+		if err := checkText("setup", s.Setup); err != nil {
+			return err
+		}
+		if err := checkText("delivery", s.Delivery); err != nil {
+			return err
+		}
+		if s.Joke != "" {
+			return errors.New("a two-part joke has a setup and delivery instead of a joke")
+		}
+	default:
+		return fmt.Errorf("invalid joke type: %s", s.Type)
+	}
+
+	if len(s.Lang) != 2 {
+		return fmt.Errorf("invalid language code: %q", s.Lang)
+	}
+	return nil
+}
+
+// checkText checks that a text field is present and not too long
+func checkText(field, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("the %s is required", field)
+	}
+	if len(text) > MaxSubmissionLength {
+		return fmt.Errorf("the %s is longer than %d characters", field, MaxSubmissionLength)
+	}
+	return nil
+}
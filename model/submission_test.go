@@ -0,0 +1,70 @@
+package model_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("Submission", func() {
+	single := func() model.Submission {
+		return model.Submission{
+			Category: model.CategoryPun,
+			Type:     model.JokeTypeSingle,
+			Joke:     "I used to be a banker, but I lost interest.",
+			Lang:     "en",
+		}
+	}
+
+	twoPart := func() model.Submission {
+		return model.Submission{
+			Category: model.CategoryProgramming,
+			Type:     model.JokeTypeTwoPart,
+			Setup:    "Why do programmers prefer dark mode?",
+			Delivery: "Because light attracts bugs.",
+			Lang:     "en",
+		}
+	}
+
+	It("should accept complete submissions", func() {
+		Expect(single().Validate()).To(Succeed())
+		Expect(twoPart().Validate()).To(Succeed())
+	})
+
+	DescribeTable("should reject incomplete or inconsistent submissions",
+		func(change func(*model.Submission), base func() model.Submission, message string) {
+			submission := base()
+			change(&submission)
+			Expect(submission.Validate()).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("no category", func(s *model.Submission) { s.Category = "" }, single, "a category is required"),
+		Entry("the Any category", func(s *model.Submission) { s.Category = "Any" }, single, "a category is required"),
+		Entry("an unknown type", func(s *model.Submission) { s.Type = "threepart" }, single, "invalid joke type"),
+		Entry("a blank joke", func(s *model.Submission) { s.Joke = "  " }, single, "the joke is required"),
+		Entry("a single joke with a delivery", func(s *model.Submission) { s.Delivery = "Boom." }, single, "no setup or delivery"),
+		Entry("a missing delivery", func(s *model.Submission) { s.Delivery = "" }, twoPart, "the delivery is required"),
+		Entry("a two-part joke with a joke", func(s *model.Submission) { s.Joke = "Boom." }, twoPart, "instead of a joke"),
+		Entry("an overlong setup", func(s *model.Submission) { s.Setup = strings.Repeat("a", model.MaxSubmissionLength+1) }, twoPart, "longer than"),
+		Entry("a missing language", func(s *model.Submission) { s.Lang = "" }, single, "invalid language code"),
+	)
+
+	It("should encode every flag and only the fields of its type", func() {
+		submission := single()
+		submission.Flags.Political = true
+
+		data, err := json.Marshal(submission)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(MatchJSON(`{
+			"formatVersion": 0,
+			"category": "Pun",
+			"type": "single",
+			"joke": "I used to be a banker, but I lost interest.",
+			"flags": {"nsfw": false, "religious": false, "political": true, "racist": false, "sexist": false, "explicit": false},
+			"lang": "en"
+		}`))
+	})
+})
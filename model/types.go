@@ -0,0 +1,190 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Category is a joke category
+type Category string
+
+// Joke categories JokeAPI had when this package was written. The client
+// discovers the current list, which is the one to validate against.
+const (
+	CategoryProgramming Category = "Programming"
+	CategoryMisc        Category = "Misc"
+	CategoryDark        Category = "Dark"
+	CategoryPun         Category = "Pun"
+	CategorySpooky      Category = "Spooky"
+	CategoryChristmas   Category = "Christmas"
+)
+
+// ParseCategory returns the category in known named by s, ignoring case.
+// known is the list of categories JokeAPI currently has.
+func ParseCategory(s string, known []string) (Category, error) {
+	for _, category := range known {
+		if strings.EqualFold(category, strings.TrimSpace(s)) {
+			return Category(category), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidCategory, s)
+}
+
+// Valid reports whether c is one of the known categories in its canonical
+// spelling
+func (c Category) Valid(known []string) bool {
+	for _, category := range known {
+		if string(c) == category {
+			return true
+		}
+	}
+	return false
+}
+
+// JokeType says whether a joke is a one-liner or has a setup and punchline
+type JokeType string
+
+// Joke types
+const (
+	JokeTypeSingle  JokeType = "single"
+	JokeTypeTwoPart JokeType = "twopart"
+)
+
+// ParseJokeType returns the joke type named by s, ignoring case and accepting
+// "two-part" for twopart
+func ParseJokeType(s string) (JokeType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "single":
+		return JokeTypeSingle, nil
+	case "twopart", "two-part":
+		return JokeTypeTwoPart, nil
+	}
+	return "", fmt.Errorf("invalid joke type: %s", s)
+}
+
+// Valid reports whether t is a known joke type
+func (t JokeType) Valid() bool {
+	return t == JokeTypeSingle || t == JokeTypeTwoPart
+}
+
+// Flag names, as used in JokeAPI's blacklistFlags
+const (
+	FlagNsfw      = "nsfw"
+	FlagReligious = "religious"
+	FlagPolitical = "political"
+	FlagRacist    = "racist"
+	FlagSexist    = "sexist"
+	FlagExplicit  = "explicit"
+)
+
+// FlagNames lists every flag Flags can represent, in JokeAPI's order
+var FlagNames = []string{FlagNsfw, FlagReligious, FlagPolitical, FlagRacist, FlagSexist, FlagExplicit}
+
+// Flags marks the kinds of content a joke contains. It can be used as a set
+// of flag names.
+type Flags struct {
+	Nsfw      bool `json:"nsfw"`
+	Religious bool `json:"religious"`
+	Political bool `json:"political"`
+	Racist    bool `json:"racist"`
+	Sexist    bool `json:"sexist"`
+	Explicit  bool `json:"explicit"`
+}
+
+// ParseFlags returns the set of the named flags
+func ParseFlags(names ...string) (Flags, error) {
+	var flags Flags
+	for _, name := range names {
+		if err := flags.Set(name); err != nil {
+			return Flags{}, err
+		}
+	}
+	return flags, nil
+}
+
+// field returns the field for a flag name, or nil if the name is unknown
+func (f *Flags) field(name string) *bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case FlagNsfw:
+		return &f.Nsfw
+	case FlagReligious:
+		return &f.Religious
+	case FlagPolitical:
+		return &f.Political
+	case FlagRacist:
+		return &f.Racist
+	case FlagSexist:
+		return &f.Sexist
+	case FlagExplicit:
+		return &f.Explicit
+	}
+	return nil
+}
+
+// Has reports whether the named flag is set
+func (f Flags) Has(name string) bool {
+	field := f.field(name)
+	return field != nil && *field
+}
+
+// Set sets the named flag
+func (f *Flags) Set(name string) error {
+	field := f.field(name)
+	if field == nil {
+		return fmt.Errorf("invalid flag: %s", name)
+	}
+	*field = true
+	return nil
+}
+
+// Names returns the names of the flags that are set, in JokeAPI's order
+func (f Flags) Names() []string {
+	var names []string
+	for _, name := range FlagNames {
+		if f.Has(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Empty reports whether no flag is set
+func (f Flags) Empty() bool {
+	return f == Flags{}
+}
+
+// Union returns the flags set in f or other
+func (f Flags) Union(other Flags) Flags {
+	return Flags{
+		Nsfw:      f.Nsfw || other.Nsfw,
+		Religious: f.Religious || other.Religious,
+		Political: f.Political || other.Political,
+		Racist:    f.Racist || other.Racist,
+		Sexist:    f.Sexist || other.Sexist,
+		Explicit:  f.Explicit || other.Explicit,
+	}
+}
+
+// Intersect returns the flags set in both f and other
+func (f Flags) Intersect(other Flags) Flags {
+	return Flags{
+		Nsfw:      f.Nsfw && other.Nsfw,
+		Religious: f.Religious && other.Religious,
+		Political: f.Political && other.Political,
+		Racist:    f.Racist && other.Racist,
+		Sexist:    f.Sexist && other.Sexist,
+		Explicit:  f.Explicit && other.Explicit,
+	}
+}
+
+// Without returns the flags set in f but not in other
+func (f Flags) Without(other Flags) Flags {
+	return f.Intersect(Flags{
+		Nsfw:      !other.Nsfw,
+		Religious: !other.Religious,
+		Political: !other.Political,
+		Racist:    !other.Racist,
+		Sexist:    !other.Sexist,
+		Explicit:  !other.Explicit,
+	})
+}
@@ -0,0 +1,76 @@
+package model_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("Domain types", func() {
+	Describe("Category", func() {
+		known := []string{"Programming", "Christmas", "Knock-Knock"}
+
+		It("should parse known categories ignoring case", func() {
+			Expect(model.ParseCategory("programming", known)).To(Equal(model.CategoryProgramming))
+			Expect(model.ParseCategory(" KNOCK-KNOCK ", known)).To(Equal(model.Category("Knock-Knock")))
+		})
+
+		It("should reject categories that are not known", func() {
+			_, err := model.ParseCategory("pun", known)
+
+			Expect(errors.Is(err, model.ErrInvalidCategory)).To(BeTrue())
+		})
+
+		It("should only treat canonical spellings of known categories as valid", func() {
+			Expect(model.CategoryChristmas.Valid(known)).To(BeTrue())
+			Expect(model.Category("christmas").Valid(known)).To(BeFalse())
+			Expect(model.CategoryPun.Valid(known)).To(BeFalse())
+		})
+	})
+
+	Describe("JokeType", func() {
+		It("should parse joke types", func() {
+			Expect(model.ParseJokeType("Single")).To(Equal(model.JokeTypeSingle))
+			Expect(model.ParseJokeType("two-part")).To(Equal(model.JokeTypeTwoPart))
+		})
+
+		It("should reject unknown joke types", func() {
+			_, err := model.ParseJokeType("threepart")
+
+			Expect(err).To(MatchError("invalid joke type: threepart"))
+			Expect(model.JokeType("threepart").Valid()).To(BeFalse())
+		})
+	})
+
+	Describe("Flags", func() {
+		It("should parse and list flag names", func() {
+			flags, err := model.ParseFlags("Explicit", "nsfw")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flags.Nsfw).To(BeTrue())
+			Expect(flags.Has("explicit")).To(BeTrue())
+			Expect(flags.Has("racist")).To(BeFalse())
+			Expect(flags.Names()).To(Equal([]string{"nsfw", "explicit"}))
+		})
+
+		It("should reject unknown flag names", func() {
+			_, err := model.ParseFlags("nsfw", "rude")
+
+			Expect(err).To(MatchError("invalid flag: rude"))
+		})
+
+		It("should support set operations", func() {
+			a, _ := model.ParseFlags("nsfw", "political", "racist")
+			b, _ := model.ParseFlags("political", "sexist")
+
+			Expect(a.Union(b).Names()).To(Equal([]string{"nsfw", "political", "racist", "sexist"}))
+			Expect(a.Intersect(b).Names()).To(Equal([]string{"political"}))
+			Expect(a.Without(b).Names()).To(Equal([]string{"nsfw", "racist"}))
+			Expect(a.Intersect(model.Flags{}).Empty()).To(BeTrue())
+			Expect(b.Empty()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AriT93/ai-agent/config"
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+// newJokeClient creates the JokeAPI client with the configured cache and
+// content policy. Safe mode is added to each request's query by the model.
+func newJokeClient(cfg config.Config, policy *jokeclient.Policy) *jokeclient.Client {
+	return jokeclient.NewClient(
+		jokeclient.WithDebug(cfg.Debug),
+		jokeclient.WithRetryPolicy(jokeclient.DefaultRetryPolicy()),
+		jokeclient.WithRateLimiter(jokeclient.NewRateLimiter(false)),
+		jokeclient.WithCircuitBreaker(jokeclient.NewCircuitBreaker(jokeclient.DefaultBreakerThreshold, jokeclient.DefaultBreakerCooldown)),
+		jokeclient.WithCache(newJokeCache(cfg), time.Duration(cfg.CacheTTL)),
+		jokeclient.WithNoRepeats(true),
+		jokeclient.WithPolicy(policy),
+	)
+}
+
+// selectProvider builds the chain of joke sources in the configured order.
+// Each source gets its own timeout and circuit breaker, failures are recorded
+// in the client's debug log, and the client's content policy is enforced on
+// every source. Batches and other JokeAPI-only features always use the
+// JokeAPI client.
+func selectProvider(cfg config.Config, client *jokeclient.Client) (jokeclient.JokeProvider, error) {
+	// The JokeAPI client enforces its policy itself; other sources are wrapped
+	policed := func(provider jokeclient.JokeProvider) jokeclient.JokeProvider {
+		wrapped := jokeclient.NewPolicyProvider(provider, client.Policy)
+		wrapped.Log = client.WriteDebug
+		return wrapped
+	}
+
+	var providers []jokeclient.JokeProvider
+	for _, name := range cfg.ProviderOrder() {
+		switch name {
+		case config.ProviderJokeAPI:
+			providers = append(providers, client)
+		case config.ProviderDadJoke:
+			providers = append(providers, policed(jokeclient.NewDadJokeProvider()))
+		case config.ProviderOfficial:
+			providers = append(providers, policed(jokeclient.NewOfficialJokeProvider()))
+		case config.ProviderLocal:
+			local, err := localProvider(cfg)
+			if err != nil {
+				return client, err
+			}
+			providers = append(providers, policed(local))
+		default:
+			return client, fmt.Errorf("unknown joke provider: %s", name)
+		}
+	}
+
+	chain := jokeclient.NewFallbackProvider(providers...)
+	for _, stage := range chain.Stages {
+		stage.Timeout = time.Duration(cfg.ProviderTimeout)
+		if stage.Provider != jokeclient.JokeProvider(client) {
+			continue
+		}
+
+		// Give the client's retries time to run rather than cutting them short
+		stage.Timeout = max(stage.Timeout, client.MaxRequestTime())
+
+		// The JokeAPI client has its own breaker, shared with batch requests
+		if client.Breaker != nil {
+			stage.Breaker = nil
+		}
+	}
+	chain.Log = client.WriteDebug
+	return chain, nil
+}
+
+// loadPolicy reads the configured content policy, or returns nil if none is set
+func loadPolicy(cfg config.Config) (*jokeclient.Policy, error) {
+	if cfg.PolicyPath == "" {
+		return nil, nil
+	}
+	return jokeclient.LoadPolicy(cfg.PolicyPath)
+}
+
+// localProvider loads the configured corpus, or the built-in one if none is set
+func localProvider(cfg config.Config) (*jokeclient.LocalProvider, error) {
+	if cfg.CorpusPath == "" {
+		return jokeclient.DefaultLocalProvider(), nil
+	}
+	return jokeclient.LoadLocalProvider(cfg.CorpusPath)
+}
+
+// newJokeCache picks the response cache: on disk under the user cache
+// directory when configured, in memory otherwise
+func newJokeCache(cfg config.Config) jokeclient.Cache {
+	if cfg.Cache == "disk" {
+		if dir, err := jokeclient.DefaultCacheDir(); err == nil {
+			if cache, err := jokeclient.NewDiskCache(dir); err == nil {
+				return cache
+			}
+		}
+	}
+	return jokeclient.NewMemoryCache(100)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AriT93/ai-agent/config"
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+var _ = Describe("selectProvider", func() {
+	var (
+		server   *httptest.Server
+		requests atomic.Int32
+		down     atomic.Bool
+		cfg      config.Config
+		client   *jokeclient.Client
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		down.Store(false)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := requests.Add(1)
+			if down.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintf(w, `{"error": false, "category": "Misc", "type": "single", "joke": "Political", "flags": {"political": true}, "id": %d, "safe": true, "lang": "en"}`, n)
+		}))
+
+		// JokeAPI, then a corpus holding one political and one clean joke
+		corpus := filepath.Join(GinkgoT().TempDir(), "jokes.json")
+		Expect(os.WriteFile(corpus, []byte(`[
+			{"category": "Misc", "type": "single", "joke": "Local political", "flags": {"political": true}, "id": 1, "safe": true, "lang": "en"},
+			{"category": "Misc", "type": "single", "joke": "Local clean", "flags": {}, "id": 2, "safe": true, "lang": "en"}
+		]`), 0644)).To(Succeed())
+		cfg = config.Default()
+		cfg.CorpusPath = corpus
+
+		client = newJokeClient(cfg, &jokeclient.Policy{BlockedFlags: []string{"political"}})
+		client.BaseURL = server.URL
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should enforce the policy on JokeAPI once, without falling back", func() {
+		provider, err := selectProvider(cfg, client)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+
+		Expect(err).To(MatchError(jokeclient.ErrPolicyViolation))
+		Expect(requests.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should give JokeAPI time for its retries", func() {
+		provider, err := selectProvider(cfg, client)
+		Expect(err).NotTo(HaveOccurred())
+
+		stages := provider.(*jokeclient.FallbackProvider).Stages
+		Expect(stages[0].Provider).To(BeIdenticalTo(client))
+		Expect(stages[0].Timeout).To(Equal(client.MaxRequestTime()))
+		Expect(stages[0].Timeout).To(BeNumerically(">", time.Duration(cfg.ProviderTimeout)))
+		Expect(stages[len(stages)-1].Timeout).To(Equal(time.Duration(cfg.ProviderTimeout)))
+	})
+
+	It("should hold the fallback corpus to the policy", func() {
+		down.Store(true)
+		client.Retry = jokeclient.RetryPolicy{}
+		provider, err := selectProvider(cfg, client)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 10; i++ {
+			joke, err := provider.FetchJokeWithQuery(context.Background(), jokeclient.JokeQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joke.Joke).To(Equal("Local clean"))
+		}
+	})
+})
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/AriT93/ai-agent/config"
+	"github.com/AriT93/ai-agent/jokeclient"
+)
+
+// checkTimeout bounds how long --check waits for JokeAPI
+const checkTimeout = 15 * time.Second
+
+// statusCmd reports JokeAPI's health for /status
+func statusCmd(ctx context.Context, id int, client *jokeclient.Client) tea.Cmd {
+	return func() tea.Msg {
+		status := client.Status(ctx)
+		if ctx.Err() != nil {
+			return errorResponseMsg{id: id, err: ctx.Err()}
+		}
+		return textResponseMsg{id: id, text: status.Report()}
+	}
+}
+
+// runCheck prints a health report for --check and returns the exit code:
+// 0 when JokeAPI is healthy, 1 when it is not
+func runCheck() int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	status := newJokeClient(cfg, nil).Status(ctx)
+	fmt.Println(status.Report())
+	if !status.Healthy() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/AriT93/ai-agent/jokeclient"
+	jokemodel "github.com/AriT93/ai-agent/model"
+)
+
+// submitStep is a question asked by the /submit flow
+type submitStep int
+
+const (
+	stepCategory submitStep = iota
+	stepType
+	stepJoke // The joke, or the setup of a two-part joke
+	stepDelivery
+	stepFlags
+	stepConfirm
+	stepDone
+)
+
+// submitFlow walks the user through a joke submission one answer at a time
+type submitFlow struct {
+	step       submitStep
+	submission jokemodel.Submission
+	dryRun     bool // Only check the submission, don't save it
+}
+
+// prompt returns the question for the current step
+func (f *submitFlow) prompt() string {
+	switch f.step {
+	case stepCategory:
+		return fmt.Sprintf("Which category? [%s]", strings.Join(jokeclient.KnownCategories(), ", "))
+	case stepType:
+		return "Is it a single joke or a twopart joke with a setup and delivery? [single, twopart]"
+	case stepJoke:
+		if f.submission.Type == jokemodel.JokeTypeTwoPart {
+			return "What's the setup?"
+		}
+		return "What's the joke?"
+	case stepDelivery:
+		return "And the delivery (punchline)?"
+	case stepFlags:
+		return fmt.Sprintf("Does it need any flags? Comma-separated, or 'none'. [%s]", strings.Join(jokeclient.KnownFlags(), ", "))
+	case stepConfirm:
+		action := "Submit it to JokeAPI?"
+		if f.dryRun {
+			action = "Check it with a dry run? Nothing will be saved."
+		}
+		return fmt.Sprintf("%s\n%s [yes, no]", f.summary(), action)
+	}
+	return ""
+}
+
+// summary describes the submission for confirmation
+func (f *submitFlow) summary() string {
+	s := f.submission
+	text := s.Joke
+	if s.Type == jokemodel.JokeTypeTwoPart {
+		text = s.Setup + " / " + s.Delivery
+	}
+	flags := "none"
+	if !s.Flags.Empty() {
+		flags = strings.Join(s.Flags.Names(), ", ")
+	}
+	return fmt.Sprintf("%s %s joke: %s (flags: %s)", s.Category, s.Type, text, flags)
+}
+
+// answer applies the user's answer to the current step and moves on. An
+// error leaves the step unchanged so the question can be asked again.
+func (f *submitFlow) answer(input string) error {
+	input = strings.TrimSpace(input)
+
+	switch f.step {
+	case stepCategory:
+		category, err := jokeclient.ParseCategory(input)
+		if err != nil {
+			return fmt.Errorf("unknown category: %q", input)
+		}
+		f.submission.Category = category
+		f.step = stepType
+
+	case stepType:
+		jokeType, err := jokemodel.ParseJokeType(input)
+		if err != nil {
+			return err
+		}
+		f.submission.Type = jokeType
+		f.step = stepJoke
+
+	case stepJoke:
+		if input == "" {
+			return fmt.Errorf("the joke can't be empty")
+		}
+		if f.submission.Type == jokemodel.JokeTypeTwoPart {
+			f.submission.Setup = input
+			f.step = stepDelivery
+		} else {
+			f.submission.Joke = input
+			f.step = stepFlags
+		}
+
+	case stepDelivery:
+		if input == "" {
+			return fmt.Errorf("the delivery can't be empty")
+		}
+		f.submission.Delivery = input
+		f.step = stepFlags
+
+	case stepFlags:
+		var names []string
+		if !strings.EqualFold(input, "none") {
+			for _, name := range strings.Split(input, ",") {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				if !slices.ContainsFunc(jokeclient.KnownFlags(), func(flag string) bool { return strings.EqualFold(flag, name) }) {
+					return fmt.Errorf("unknown flag: %q", name)
+				}
+				names = append(names, name)
+			}
+		}
+		flags, err := jokemodel.ParseFlags(names...)
+		if err != nil {
+			return err
+		}
+		f.submission.Flags = flags
+		f.step = stepConfirm
+
+	case stepConfirm:
+		switch strings.ToLower(input) {
+		case "yes", "y":
+			f.step = stepDone
+		case "no", "n":
+			return errSubmitCancelled
+		default:
+			return fmt.Errorf("please answer yes or no")
+		}
+	}
+	return nil
+}
+
+// errSubmitCancelled ends the flow without submitting
+var errSubmitCancelled = errors.New("submission cancelled")
+
+// startSubmit handles "/submit [--dry-run]", beginning the question flow
+func (m model) startSubmit(args []string) model {
+	flow := &submitFlow{}
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "--dry-run", "dry-run", "-n":
+			flow.dryRun = true
+		default:
+			return m.addMessage("Usage: /submit [--dry-run]")
+		}
+	}
+
+	m.submit = flow
+	return m.addMessage("Let's submit a joke to JokeAPI. Type /cancel to stop at any time.\n" + flow.prompt())
+}
+
+// continueSubmit feeds an answer to the submission flow, sending the joke
+// once every question has been answered
+func (m model) continueSubmit(input string) (model, tea.Cmd) {
+	if strings.EqualFold(strings.TrimSpace(input), "/cancel") {
+		m.submit = nil
+		return m.addMessage("Submission cancelled."), nil
+	}
+
+	flow := m.submit
+	if err := flow.answer(input); err != nil {
+		if errors.Is(err, errSubmitCancelled) {
+			m.submit = nil
+			return m.addMessage("Submission cancelled."), nil
+		}
+		return m.addMessage("Error: " + err.Error() + "\n" + flow.prompt()), nil
+	}
+
+	if flow.step != stepDone {
+		return m.addMessage(flow.prompt()), nil
+	}
+
+	m.submit = nil
+	return m.startRequest(func(ctx context.Context, id int) tea.Cmd {
+		return submitJokeCmd(ctx, id, m.jokeClient, flow.submission, flow.dryRun)
+	})
+}
+
+// submitJokeCmd sends a submission and reports JokeAPI's reply
+func submitJokeCmd(ctx context.Context, id int, client *jokeclient.Client, submission jokemodel.Submission, dryRun bool) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := client.SubmitJoke(ctx, submission, dryRun)
+		if err != nil {
+			return errorResponseMsg{id: id, err: err}
+		}
+		return textResponseMsg{id: id, text: "JokeAPI: " + resp.Message}
+	}
+}
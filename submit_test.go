@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	jokemodel "github.com/AriT93/ai-agent/model"
+)
+
+var _ = Describe("submitFlow", func() {
+	twoPart := jokemodel.Submission{Category: jokemodel.CategoryPun, Type: jokemodel.JokeTypeTwoPart}
+	single := jokemodel.Submission{Category: jokemodel.CategoryPun, Type: jokemodel.JokeTypeSingle}
+
+	DescribeTable("moving between steps",
+		func(flow submitFlow, input string, next submitStep, check func(jokemodel.Submission)) {
+			Expect(flow.answer(input)).To(Succeed())
+
+			Expect(flow.step).To(Equal(next))
+			if check != nil {
+				check(flow.submission)
+			}
+		},
+		Entry("category in any case", submitFlow{step: stepCategory}, " programming ", stepType,
+			func(s jokemodel.Submission) { Expect(s.Category).To(Equal(jokemodel.CategoryProgramming)) }),
+		Entry("two-part type", submitFlow{step: stepType}, "two-part", stepJoke,
+			func(s jokemodel.Submission) { Expect(s.Type).To(Equal(jokemodel.JokeTypeTwoPart)) }),
+		Entry("single joke skips the delivery", submitFlow{step: stepJoke, submission: single}, "A pun", stepFlags,
+			func(s jokemodel.Submission) { Expect(s.Joke).To(Equal("A pun")) }),
+		Entry("setup of a two-part joke", submitFlow{step: stepJoke, submission: twoPart}, "Why?", stepDelivery,
+			func(s jokemodel.Submission) { Expect(s.Setup).To(Equal("Why?")) }),
+		Entry("delivery", submitFlow{step: stepDelivery, submission: twoPart}, "Because.", stepFlags,
+			func(s jokemodel.Submission) { Expect(s.Delivery).To(Equal("Because.")) }),
+		Entry("no flags", submitFlow{step: stepFlags}, "None", stepConfirm,
+			func(s jokemodel.Submission) { Expect(s.Flags.Empty()).To(BeTrue()) }),
+		Entry("several flags", submitFlow{step: stepFlags}, "NSFW, political,", stepConfirm,
+			func(s jokemodel.Submission) { Expect(s.Flags.Names()).To(Equal([]string{"nsfw", "political"})) }),
+		Entry("confirmation", submitFlow{step: stepConfirm}, "y", stepDone, nil),
+	)
+
+	DescribeTable("rejecting invalid answers",
+		func(step submitStep, input, message string) {
+			flow := submitFlow{step: step, submission: twoPart}
+
+			Expect(flow.answer(input)).To(MatchError(message))
+			Expect(flow.step).To(Equal(step))
+		},
+		Entry("unknown category", stepCategory, "Knock-Knock", `unknown category: "Knock-Knock"`),
+		Entry("unknown type", stepType, "threepart", "invalid joke type: threepart"),
+		Entry("empty joke", stepJoke, " ", "the joke can't be empty"),
+		Entry("empty delivery", stepDelivery, "", "the delivery can't be empty"),
+		Entry("unknown flag", stepFlags, "nsfw, gross", `unknown flag: "gross"`),
+		Entry("neither yes nor no", stepConfirm, "maybe", "please answer yes or no"),
+	)
+
+	Describe("cancelling", func() {
+		It("should cancel when the submission is not confirmed", func() {
+			flow := submitFlow{step: stepConfirm}
+
+			Expect(flow.answer("no")).To(MatchError(errSubmitCancelled))
+		})
+
+		DescribeTable("ending the flow",
+			func(step submitStep, input string) {
+				m := model{viewport: viewport.New(80, 20), submit: &submitFlow{step: step}}
+
+				m, cmd := m.continueSubmit(input)
+
+				Expect(cmd).To(BeNil())
+				Expect(m.submit).To(BeNil())
+				Expect(m.messages).To(Equal([]string{"Submission cancelled."}))
+			},
+			Entry("/cancel at any step", stepJoke, "/CANCEL"),
+			Entry("no at the confirmation", stepConfirm, "n"),
+		)
+	})
+})